@@ -0,0 +1,121 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ==========================================
+// 认证 (Authenticator)
+// ==========================================
+
+// Authenticator 认证器，负责在 Client 上建立可用的会话 (登录、设置 Cookie 或请求头等)。
+// 多个认证器可通过 ChainAuth / FirstOfAuth 组合，例如先满足反向代理的 Basic Auth，再完成 Vertex 登录。
+type Authenticator interface {
+	Authenticate(ctx context.Context, c *Client) error
+}
+
+// AuthenticatorFunc 允许将普通函数作为 Authenticator 使用
+type AuthenticatorFunc func(ctx context.Context, c *Client) error
+
+// Authenticate 实现 Authenticator
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, c *Client) error {
+	return f(ctx, c)
+}
+
+// WithAuthenticator 配置认证器，NewClient 会在应用完所有选项后执行认证
+func WithAuthenticator(a Authenticator) ClientOption {
+	return func(c *Client) error {
+		c.auth = a
+		return nil
+	}
+}
+
+// Authenticate 重新执行配置的认证器，可用于会话过期后重新登录
+func (c *Client) Authenticate(ctx context.Context) error {
+	if c.auth == nil {
+		return nil
+	}
+	return c.auth.Authenticate(ctx, c)
+}
+
+// CheckSession 检查当前会话是否有效
+func (c *Client) CheckSession(ctx context.Context) error {
+	_, err := c.get(ctx, "/api/user/get", nil)
+	return err
+}
+
+// PasswordAuth 使用明文密码登录 Vertex (SDK 负责计算 MD5)
+func PasswordAuth(username, password string) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+		return c.Login(ctx, username, password)
+	})
+}
+
+// HashedPasswordAuth 使用预先计算好的 MD5 密码 (32 位十六进制) 登录 Vertex，
+// 适合从密钥管理系统中读取哈希而不接触明文密码的场景
+func HashedPasswordAuth(username, md5Password string) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+		return c.LoginHashed(ctx, username, md5Password)
+	})
+}
+
+// CookieAuth 使用已有的会话 Cookie (原始字符串格式) 认证，并通过 /api/user/get 校验其有效性
+func CookieAuth(cookies string) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+		if cookies == "" {
+			return errors.New("Cookie 为空")
+		}
+		if err := c.SetCookies(cookies); err != nil {
+			return err
+		}
+		if err := c.CheckSession(ctx); err != nil {
+			return fmt.Errorf("Cookie 已失效: %w", err)
+		}
+		return nil
+	})
+}
+
+// BasicAuth 为所有请求附加 HTTP Basic Auth，用于通过 Nginx / Authelia 等反向代理的认证
+func BasicAuth(username, password string) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+		c.Req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// HeaderAuth 为所有请求附加自定义请求头，如反向代理要求的 API Token
+func HeaderAuth(headers map[string]string) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+		c.Req.SetHeaders(headers)
+		return nil
+	})
+}
+
+// ChainAuth 依次执行所有认证器，任一失败即返回错误
+func ChainAuth(auths ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+		for _, a := range auths {
+			if err := a.Authenticate(ctx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FirstOfAuth 依次尝试认证器，直到其中一个成功；全部失败时返回所有错误
+func FirstOfAuth(auths ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+		var errs []error
+		for _, a := range auths {
+			err := a.Authenticate(ctx, c)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+}
@@ -0,0 +1,67 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// sessionRoutes 只有携带 sid=valid 的请求才能通过 /api/user/get，登录成功后下发该 Cookie
+func sessionRoutes() map[string]testRoute {
+	return map[string]testRoute{
+		"/api/user/get": func(r *http.Request) (interface{}, error) {
+			if c, err := r.Cookie("sid"); err != nil || c.Value != "valid" {
+				return nil, errors.New("未登录")
+			}
+			return map[string]string{"username": "admin"}, nil
+		},
+		"/api/user/login": staticRoute(nil),
+	}
+}
+
+func TestWithAuth(t *testing.T) {
+	tests := []struct {
+		name                 string
+		user, pass, cookies  string
+		wantErr              bool
+		wantLogin, wantCheck int
+	}{
+		{name: "仅 Cookie 且有效", cookies: "sid=valid", wantCheck: 0},
+		// 与旧版本一致：只提供 Cookie 时不因 Cookie 失效而创建失败
+		{name: "仅 Cookie 且失效", cookies: "sid=expired", wantCheck: 0},
+		{name: "Cookie 有效时不登录", user: "admin", pass: "pass", cookies: "sid=valid", wantCheck: 1},
+		{name: "Cookie 失效时使用密码登录", user: "admin", pass: "pass", cookies: "sid=expired", wantCheck: 1, wantLogin: 1},
+		{name: "仅密码", user: "admin", pass: "pass", wantLogin: 1},
+		{name: "未提供凭据"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newTestClient(t, sessionRoutes(), WithAuth(tt.user, tt.pass, tt.cookies))
+			if c == nil {
+				t.Fatal("NewClient 返回了 nil")
+			}
+			if got := srv.Calls("/api/user/login"); got != tt.wantLogin {
+				t.Errorf("登录 %d 次, 期望 %d 次", got, tt.wantLogin)
+			}
+			if got := srv.Calls("/api/user/get"); got != tt.wantCheck {
+				t.Errorf("校验会话 %d 次, 期望 %d 次", got, tt.wantCheck)
+			}
+			if tt.cookies != "" {
+				cookies, _ := c.GetCookies()
+				if cookies == "" {
+					t.Error("Cookie 未设置")
+				}
+			}
+		})
+	}
+}
+
+func TestCookieAuthValidates(t *testing.T) {
+	_, url := newTestServer(t, sessionRoutes())
+	_, err := NewClient(context.Background(), url, WithoutRetry(), WithAuthenticator(CookieAuth("sid=expired")))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, 期望 Cookie 失效的 APIError", err)
+	}
+}
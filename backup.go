@@ -0,0 +1,483 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// 配置备份与恢复 (Backup / Restore)
+// ==========================================
+
+// BackupVersion 当前备份格式版本
+const BackupVersion = 1
+
+// BackupArchive 一份完整的 Vertex 配置快照。
+// 站点与通知等 SDK 尚未覆盖的配置不在备份范围内。
+type BackupArchive struct {
+	Version     int                `json:"version"`     // 备份格式版本
+	CreatedAt   time.Time          `json:"createdAt"`   // 备份时间
+	Source      string             `json:"source"`      // 来源 Vertex 地址
+	Servers     []Server           `json:"servers"`     // 服务器
+	Downloaders []DownloaderConfig `json:"downloaders"` // 下载器
+	Rss         []RssConfig        `json:"rss"`         // RSS 任务
+	RssRules    []RssRule          `json:"rssRules"`    // 选种规则
+	DeleteRules []DeleteRule       `json:"deleteRules"` // 删种规则
+}
+
+// Snapshot 读取当前实例的全部配置
+func (c *Client) Snapshot(ctx context.Context) (*BackupArchive, error) {
+	a := &BackupArchive{Version: BackupVersion, CreatedAt: time.Now(), Source: c.BaseURL}
+	var err error
+	if a.Servers, err = c.ListServers(ctx); err != nil {
+		return nil, fmt.Errorf("读取服务器失败: %w", err)
+	}
+	downloaders, err := c.ListDownloaders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("读取下载器失败: %w", err)
+	}
+	for _, d := range downloaders {
+		a.Downloaders = append(a.Downloaders, d.DownloaderConfig)
+	}
+	if a.Rss, err = c.ListRss(ctx); err != nil {
+		return nil, fmt.Errorf("读取 RSS 任务失败: %w", err)
+	}
+	if a.RssRules, err = c.ListRssRules(ctx); err != nil {
+		return nil, fmt.Errorf("读取选种规则失败: %w", err)
+	}
+	if a.DeleteRules, err = c.ListDeleteRules(ctx); err != nil {
+		return nil, fmt.Errorf("读取删种规则失败: %w", err)
+	}
+	return a, nil
+}
+
+// BackupOption 备份读写选项
+type BackupOption func(*backupOptions)
+
+type backupOptions struct {
+	key       []byte
+	plaintext bool
+}
+
+// WithBackupKey 使用主密钥 (16/24/32 字节) 对备份做信封加密，加密备份中保存密码原文。
+// 读取加密备份时也需要提供相同的密钥。
+func WithBackupKey(key []byte) BackupOption {
+	return func(o *backupOptions) {
+		o.key = key
+	}
+}
+
+// WithPlaintextSecrets 以明文保存密码。不推荐，优先使用 WithBackupKey
+func WithPlaintextSecrets() BackupOption {
+	return func(o *backupOptions) {
+		o.plaintext = true
+	}
+}
+
+// Backup 将当前实例的全部配置以 JSON 写入 w。
+// 默认情况下密码会被脱敏，这样的备份无法直接恢复：Restore 遇到脱敏密码时返回 ErrRedactedSecret，
+// 只能设置 RestoreOption.SkipRedactedSecrets 跳过相关服务器与下载器。
+// 需要完整恢复时请使用 WithBackupKey 加密保存 (或 WithPlaintextSecrets 明文保存)。
+func (c *Client) Backup(ctx context.Context, w io.Writer, opts ...BackupOption) error {
+	a, err := c.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	return WriteBackup(w, a, opts...)
+}
+
+// revealedBackup 包含密码原文的备份，仅用于加密或明文备份
+type revealedBackup struct {
+	*BackupArchive
+	Servers     []revealedServer           `json:"servers"`
+	Downloaders []revealedDownloaderConfig `json:"downloaders"`
+}
+
+// WriteBackup 将备份以 JSON 写入 w
+func WriteBackup(w io.Writer, a *BackupArchive, opts ...BackupOption) error {
+	var o backupOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var v interface{} = a
+	if o.key != nil || o.plaintext {
+		rb := revealedBackup{BackupArchive: a}
+		for _, s := range a.Servers {
+			rb.Servers = append(rb.Servers, s.revealed())
+		}
+		for _, d := range a.Downloaders {
+			rb.Downloaders = append(rb.Downloaders, d.revealed())
+		}
+		v = rb
+	}
+	if o.key != nil {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if v, err = SealEnvelope(o.key, data); err != nil {
+			return fmt.Errorf("加密备份失败: %w", err)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// ReadBackup 读取并校验备份，加密备份需要通过 WithBackupKey 提供密钥
+func ReadBackup(r io.Reader, opts ...BackupOption) (*BackupArchive, error) {
+	var o backupOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var env Envelope
+	if json.Unmarshal(data, &env) == nil && env.Ciphertext != nil {
+		if o.key == nil {
+			return nil, errors.New("备份已加密，请通过 WithBackupKey 提供密钥")
+		}
+		if data, err = env.Open(o.key); err != nil {
+			return nil, err
+		}
+	}
+
+	var a BackupArchive
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("解析备份失败: %w", err)
+	}
+	if a.Version < 1 || a.Version > BackupVersion {
+		return nil, fmt.Errorf("不支持的备份版本: %d", a.Version)
+	}
+	return &a, nil
+}
+
+// IDMap 旧 ID 到新 ID 的映射，按对象类型分开
+type IDMap struct {
+	Servers     map[string]string `json:"servers"`
+	Downloaders map[string]string `json:"downloaders"`
+	Rss         map[string]string `json:"rss"`
+	RssRules    map[string]string `json:"rssRules"`
+	DeleteRules map[string]string `json:"deleteRules"`
+}
+
+// NewIDMap 创建空的 ID 映射
+func NewIDMap() IDMap {
+	return IDMap{
+		Servers:     make(map[string]string),
+		Downloaders: make(map[string]string),
+		Rss:         make(map[string]string),
+		RssRules:    make(map[string]string),
+		DeleteRules: make(map[string]string),
+	}
+}
+
+// RestoreOption 恢复选项
+type RestoreOption struct {
+	// ReuseExisting 目标实例已存在同别名对象时直接复用其 ID，而不是重复创建
+	ReuseExisting bool
+	// Key 加密备份的主密钥
+	Key []byte
+	// SkipRedactedSecrets 跳过密码已脱敏的服务器与下载器 (记录警告)。
+	// 默认情况下备份中存在脱敏密码时拒绝恢复，且不做任何修改
+	SkipRedactedSecrets bool
+}
+
+// RestoreResult 恢复结果
+type RestoreResult struct {
+	IDs      IDMap    // 旧 ID 到新 ID 的映射
+	Created  int      // 新建的对象数量
+	Reused   int      // 复用的已有对象数量
+	Warnings []string // 非致命问题，如引用了备份中不存在的对象
+}
+
+// Restore 从备份中恢复全部配置到当前实例，并将对象间的引用 (如 RssConfig.Client、
+// DownloaderConfig.DeleteRules) 重新映射到新建对象的 ID。
+// 出错时立即返回，已恢复部分的映射保存在返回的结果中。
+func (c *Client) Restore(ctx context.Context, r io.Reader, opt RestoreOption) (*RestoreResult, error) {
+	a, err := ReadBackup(r, WithBackupKey(opt.Key))
+	if err != nil {
+		return nil, err
+	}
+	return c.RestoreArchive(ctx, a, opt)
+}
+
+// RestoreArchive 恢复已读取的备份
+func (c *Client) RestoreArchive(ctx context.Context, a *BackupArchive, opt RestoreOption) (*RestoreResult, error) {
+	res := &RestoreResult{IDs: NewIDMap()}
+	rs := &restorer{c: c, reuse: opt.ReuseExisting, res: res, reused: make(map[string]bool)}
+	a, err := rs.checkSecrets(a, opt.SkipRedactedSecrets)
+	if err != nil {
+		return res, err
+	}
+	return res, rs.restore(ctx, a)
+}
+
+// restoreObject 对象的 ID 与别名，用于在创建后找回新 ID
+type restoreObject struct {
+	ID    string
+	Alias string
+}
+
+// restoreKind 一类可恢复对象的列表与映射
+type restoreKind struct {
+	name string
+	ids  map[string]string
+	list func(ctx context.Context) ([]restoreObject, error)
+}
+
+// restorer 按依赖顺序创建对象并维护 ID 映射，备份恢复与实例迁移共用
+type restorer struct {
+	c     *Client
+	reuse bool
+	res   *RestoreResult
+
+	// reused 复用的已有对象 (新 ID)，恢复时不会修改这些对象
+	reused map[string]bool
+	// beforeCreate 在发出创建请求前调用，existing 为创建前的对象列表
+	beforeCreate func(kind, oldID, alias string, existing []restoreObject) error
+	// afterCreate 在每个对象创建 (或复用) 完成后调用
+	afterCreate func(kind, oldID, newID string) error
+}
+
+func (rs *restorer) kinds() (servers, downloaders, rss, rssRules, deleteRules restoreKind) {
+	c, ids := rs.c, rs.res.IDs
+	servers = restoreKind{"服务器", ids.Servers, func(ctx context.Context) ([]restoreObject, error) {
+		list, err := c.ListServers(ctx)
+		objs := make([]restoreObject, len(list))
+		for i, s := range list {
+			objs[i] = restoreObject{s.ID, s.Alias}
+		}
+		return objs, err
+	}}
+	downloaders = restoreKind{"下载器", ids.Downloaders, func(ctx context.Context) ([]restoreObject, error) {
+		list, err := c.ListDownloaders(ctx)
+		objs := make([]restoreObject, len(list))
+		for i, d := range list {
+			objs[i] = restoreObject{d.ID, d.Alias}
+		}
+		return objs, err
+	}}
+	rss = restoreKind{"RSS 任务", ids.Rss, func(ctx context.Context) ([]restoreObject, error) {
+		list, err := c.ListRss(ctx)
+		objs := make([]restoreObject, len(list))
+		for i, r := range list {
+			objs[i] = restoreObject{r.ID, r.Alias}
+		}
+		return objs, err
+	}}
+	rssRules = restoreKind{"选种规则", ids.RssRules, func(ctx context.Context) ([]restoreObject, error) {
+		list, err := c.ListRssRules(ctx)
+		objs := make([]restoreObject, len(list))
+		for i, r := range list {
+			objs[i] = restoreObject{r.ID, r.Alias}
+		}
+		return objs, err
+	}}
+	deleteRules = restoreKind{"删种规则", ids.DeleteRules, func(ctx context.Context) ([]restoreObject, error) {
+		list, err := c.ListDeleteRules(ctx)
+		objs := make([]restoreObject, len(list))
+		for i, r := range list {
+			objs[i] = restoreObject{r.ID, r.Alias}
+		}
+		return objs, err
+	}}
+	return
+}
+
+func (rs *restorer) restore(ctx context.Context, a *BackupArchive) error {
+	servers, downloaders, rss, rssRules, deleteRules := rs.kinds()
+
+	for _, s := range a.Servers {
+		s := s
+		if err := rs.create(ctx, servers, s.ID, s.Alias, func() error {
+			s.ID = ""
+			return rs.c.AddServer(ctx, s)
+		}); err != nil {
+			return err
+		}
+	}
+	for _, r := range a.DeleteRules {
+		r := r
+		if err := rs.create(ctx, deleteRules, r.ID, r.Alias, func() error {
+			r.ID = ""
+			return rs.c.AddDeleteRule(ctx, r)
+		}); err != nil {
+			return err
+		}
+	}
+	for _, r := range a.RssRules {
+		r := r
+		if err := rs.create(ctx, rssRules, r.ID, r.Alias, func() error {
+			r.ID = ""
+			return rs.c.AddRssRules(ctx, r)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// 下载器之间通过 SameServerClients 互相引用，先创建全部下载器，再补充该字段
+	for _, d := range a.Downloaders {
+		d := d
+		if err := rs.create(ctx, downloaders, d.ID, d.Alias, func() error {
+			d.ID = ""
+			d.DeleteRules = rs.remapAll(deleteRules, d.Alias, d.DeleteRules)
+			d.RejectDeleteRules = rs.remapAll(deleteRules, d.Alias, d.RejectDeleteRules)
+			d.SameServerClients = nil
+			return rs.c.AddDownloader(ctx, d)
+		}); err != nil {
+			return err
+		}
+	}
+	for _, d := range a.Downloaders {
+		newID, ok := downloaders.ids[d.ID]
+		if !ok || rs.reused[newID] || len(d.SameServerClients) == 0 {
+			continue
+		}
+		d.ID = newID
+		d.DeleteRules = rs.remapAll(deleteRules, d.Alias, d.DeleteRules)
+		d.RejectDeleteRules = rs.remapAll(deleteRules, d.Alias, d.RejectDeleteRules)
+		d.SameServerClients = rs.remapAll(downloaders, d.Alias, d.SameServerClients)
+		if err := rs.c.ModifyDownloader(ctx, d); err != nil {
+			return fmt.Errorf("更新下载器 %s 的同服务器下载器失败: %w", d.Alias, err)
+		}
+	}
+
+	for _, r := range a.Rss {
+		r := r
+		if err := rs.create(ctx, rss, r.ID, r.Alias, func() error {
+			r.ID = ""
+			if r.Client != "" {
+				if ids := rs.remapAll(downloaders, r.Alias, []string{r.Client}); len(ids) == 1 {
+					r.Client = ids[0]
+				} else {
+					r.Client = ""
+				}
+			}
+			r.AcceptRules = rs.remapAll(rssRules, r.Alias, r.AcceptRules)
+			r.RejectRules = rs.remapAll(rssRules, r.Alias, r.RejectRules)
+			r.SameServerClients = rs.remapAll(downloaders, r.Alias, r.SameServerClients)
+			return rs.c.AddRss(ctx, r)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create 创建单个对象并记录新 ID。已记录过映射的对象会被跳过 (用于断点续传)。
+func (rs *restorer) create(ctx context.Context, kind restoreKind, oldID, alias string, add func() error) error {
+	if _, done := kind.ids[oldID]; done {
+		return nil
+	}
+	existing, err := kind.list(ctx)
+	if err != nil {
+		return fmt.Errorf("读取%s列表失败: %w", kind.name, err)
+	}
+
+	newID := ""
+	if rs.reuse {
+		for _, o := range existing {
+			if o.Alias == alias {
+				newID = o.ID
+				rs.reused[newID] = true
+				rs.res.Reused++
+				break
+			}
+		}
+	}
+	if newID == "" {
+		if rs.beforeCreate != nil {
+			if err := rs.beforeCreate(kind.name, oldID, alias, existing); err != nil {
+				return err
+			}
+		}
+		if err := add(); err != nil {
+			return fmt.Errorf("创建%s %s 失败: %w", kind.name, alias, err)
+		}
+		// Vertex 的添加接口不返回新 ID，通过对比前后列表找回
+		known := make(map[string]bool, len(existing))
+		for _, o := range existing {
+			known[o.ID] = true
+		}
+		after, err := kind.list(ctx)
+		if err != nil {
+			return fmt.Errorf("读取%s列表失败: %w", kind.name, err)
+		}
+		for _, o := range after {
+			if !known[o.ID] && o.Alias == alias {
+				newID = o.ID
+				break
+			}
+		}
+		if newID == "" {
+			return fmt.Errorf("%s %s 已创建，但无法确定其新 ID", kind.name, alias)
+		}
+		rs.res.Created++
+	}
+
+	kind.ids[oldID] = newID
+	if rs.afterCreate != nil {
+		return rs.afterCreate(kind.name, oldID, newID)
+	}
+	return nil
+}
+
+// checkSecrets 检查密码已脱敏的服务器与下载器，这些对象无法恢复。
+// skip 为 false 时返回 ErrRedactedSecret；为 true 时返回移除了这些对象的副本 (不修改 a) 并记录警告。
+func (rs *restorer) checkSecrets(a *BackupArchive, skip bool) (*BackupArchive, error) {
+	var redacted []string
+	out := *a
+	out.Servers, out.Downloaders = nil, nil
+	for _, s := range a.Servers {
+		if s.Password.IsRedacted() {
+			redacted = append(redacted, "服务器 "+s.Alias)
+			continue
+		}
+		out.Servers = append(out.Servers, s)
+	}
+	for _, d := range a.Downloaders {
+		if d.Password.IsRedacted() {
+			redacted = append(redacted, "下载器 "+d.Alias)
+			continue
+		}
+		out.Downloaders = append(out.Downloaders, d)
+	}
+	if len(redacted) == 0 {
+		return a, nil
+	}
+	if !skip {
+		return nil, fmt.Errorf("%w: %s。请使用 WithBackupKey 加密备份，或设置 SkipRedactedSecrets 跳过这些对象",
+			ErrRedactedSecret, strings.Join(redacted, "、"))
+	}
+	for _, name := range redacted {
+		rs.res.Warnings = append(rs.res.Warnings, fmt.Sprintf("%s 的密码已脱敏，已跳过，请手动添加", name))
+	}
+	return &out, nil
+}
+
+// remapAll 将引用的旧 ID 替换为新 ID，无法映射的引用会被移除并记录警告
+func (rs *restorer) remapAll(kind restoreKind, owner string, ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if newID, ok := kind.ids[id]; ok {
+			out = append(out, newID)
+			continue
+		}
+		rs.res.Warnings = append(rs.res.Warnings, fmt.Sprintf("%s 引用的%s %s 不存在，已移除该引用", owner, kind.name, id))
+	}
+	return out
+}
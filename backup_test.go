@@ -0,0 +1,523 @@
+package vertex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeStore 以内存保存服务器、下载器、RSS 与规则，模拟 list/add/modify/delete 接口。
+// 新建对象的 ID 为 "<资源>-<序号>"。
+type fakeStore struct {
+	mu      sync.Mutex
+	objects map[string][]map[string]interface{} // 资源名 (如 downloader) -> 对象列表
+	seq     int
+	failOn  string                   // 别名等于该值的 add/modify 请求返回业务错误
+	bodies  []map[string]interface{} // 所有 add/modify 请求体
+}
+
+// fakeResources fakeStore 支持的资源
+var fakeResources = []string{"server", "downloader", "rss", "rssRule", "deleteRule"}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{objects: make(map[string][]map[string]interface{})}
+}
+
+// routes 生成 fakeStore 对应的接口
+func (f *fakeStore) routes() map[string]testRoute {
+	routes := make(map[string]testRoute)
+	for _, res := range fakeResources {
+		res := res
+		routes["/api/"+res+"/list"] = func(*http.Request) (interface{}, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			out := make([]map[string]interface{}, len(f.objects[res]))
+			copy(out, f.objects[res])
+			return out, nil
+		}
+		for _, op := range []string{"add", "modify"} {
+			op := op
+			routes["/api/"+res+"/"+op] = func(r *http.Request) (interface{}, error) {
+				var obj map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+					return nil, err
+				}
+				f.mu.Lock()
+				defer f.mu.Unlock()
+				f.bodies = append(f.bodies, obj)
+				if f.failOn != "" && obj["alias"] == f.failOn {
+					return nil, errors.New("模拟失败")
+				}
+				if op == "add" {
+					f.seq++
+					obj["id"] = fmt.Sprintf("%s-%d", res, f.seq)
+					f.objects[res] = append(f.objects[res], obj)
+					return nil, nil
+				}
+				for i, old := range f.objects[res] {
+					if old["id"] == obj["id"] {
+						f.objects[res][i] = obj
+						return nil, nil
+					}
+				}
+				return nil, errors.New("对象不存在")
+			}
+		}
+	}
+	return routes
+}
+
+// find 按别名查找对象
+func (f *fakeStore) find(res, alias string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, obj := range f.objects[res] {
+		if obj["alias"] == alias {
+			return obj
+		}
+	}
+	return nil
+}
+
+// put 直接写入对象
+func (f *fakeStore) put(res string, obj map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[res] = append(f.objects[res], obj)
+}
+
+// sentPasswords 返回所有请求体中出现过的 password 字段
+func (f *fakeStore) sentPasswords() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, b := range f.bodies {
+		if pw, ok := b["password"].(string); ok {
+			out = append(out, pw)
+		}
+	}
+	return out
+}
+
+// redactedArchive 包含一个脱敏密码下载器的备份，RSS 任务引用该下载器
+func redactedArchive() *BackupArchive {
+	return &BackupArchive{
+		Version: BackupVersion,
+		Servers: []Server{{ID: "s1", Alias: "vps", Password: "root-pw"}},
+		Downloaders: []DownloaderConfig{
+			{ID: "d1", Alias: "qb", Password: "qb-pw"},
+			{ID: "d2", Alias: "tr", Password: Secret(redactedValue)},
+		},
+		Rss: []RssConfig{{ID: "r1", Alias: "feed", Client: "d2"}},
+	}
+}
+
+func TestRestoreRefusesRedactedSecrets(t *testing.T) {
+	store := newFakeStore()
+	c, _ := newTestClient(t, store.routes())
+
+	res, err := c.RestoreArchive(context.Background(), redactedArchive(), RestoreOption{})
+	if !errors.Is(err, ErrRedactedSecret) {
+		t.Fatalf("期望 ErrRedactedSecret，实际: %v", err)
+	}
+	if !strings.Contains(err.Error(), "下载器 tr") {
+		t.Fatalf("错误中应列出脱敏的对象: %v", err)
+	}
+	if len(store.bodies) != 0 || res.Created != 0 {
+		t.Fatalf("拒绝恢复时不应修改目标实例，实际请求: %v", store.bodies)
+	}
+}
+
+func TestRestoreSkipRedactedSecrets(t *testing.T) {
+	store := newFakeStore()
+	c, _ := newTestClient(t, store.routes())
+	a := redactedArchive()
+
+	res, err := c.RestoreArchive(context.Background(), a, RestoreOption{SkipRedactedSecrets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.find("downloader", "tr") != nil {
+		t.Fatal("脱敏密码的下载器应被跳过")
+	}
+	if qb := store.find("downloader", "qb"); qb == nil || qb["password"] != "qb-pw" {
+		t.Fatalf("下载器 qb 应以原文密码创建: %v", qb)
+	}
+	if feed := store.find("rss", "feed"); feed == nil || feed["client"] != "" {
+		t.Fatalf("引用被跳过下载器的 RSS 任务应移除该引用: %v", feed)
+	}
+	for _, pw := range store.sentPasswords() {
+		if pw == redactedValue {
+			t.Fatal("不应向 Vertex 发送脱敏密码")
+		}
+	}
+	if res.Created != 3 || len(res.Warnings) != 2 {
+		t.Fatalf("恢复结果不符合预期: %+v", res)
+	}
+	if len(a.Downloaders) != 2 {
+		t.Fatal("不应修改传入的备份")
+	}
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := newFakeStore()
+	src.put("server", map[string]interface{}{"id": "s1", "alias": "vps", "password": "root-pw"})
+	src.put("downloader", map[string]interface{}{"id": "d1", "alias": "qb", "password": "qb-pw", "deleteRules": []string{"x1"}})
+	src.put("deleteRule", map[string]interface{}{"id": "x1", "alias": "clean"})
+	srcClient, _ := newTestClient(t, src.routes())
+	key := bytes.Repeat([]byte{7}, 32)
+
+	// 默认备份中密码已脱敏，恢复时拒绝
+	var plain bytes.Buffer
+	if err := srcClient.Backup(ctx, &plain); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain.String(), "qb-pw") {
+		t.Fatal("未加密的备份不应包含密码原文")
+	}
+	dst := newFakeStore()
+	dstClient, _ := newTestClient(t, dst.routes())
+	if _, err := dstClient.Restore(ctx, &plain, RestoreOption{}); !errors.Is(err, ErrRedactedSecret) {
+		t.Fatalf("期望 ErrRedactedSecret，实际: %v", err)
+	}
+
+	// 加密备份保留原文，恢复后引用指向新 ID
+	var sealed bytes.Buffer
+	if err := srcClient.Backup(ctx, &sealed, WithBackupKey(key)); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed.String(), "qb-pw") {
+		t.Fatal("加密备份不应包含密码原文")
+	}
+	if _, err := dstClient.Restore(ctx, bytes.NewReader(sealed.Bytes()), RestoreOption{}); err == nil {
+		t.Fatal("缺少密钥时期望出错")
+	}
+	res, err := dstClient.Restore(ctx, &sealed, RestoreOption{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	qb := dst.find("downloader", "qb")
+	if qb == nil || qb["password"] != "qb-pw" {
+		t.Fatalf("下载器密码应为原文: %v", qb)
+	}
+	if rules := qb["deleteRules"].([]interface{}); len(rules) != 1 || rules[0] != res.IDs.DeleteRules["x1"] {
+		t.Fatalf("删种规则引用应映射到新 ID: %v, %v", rules, res.IDs.DeleteRules)
+	}
+	if vps := dst.find("server", "vps"); vps == nil || vps["password"] != "root-pw" {
+		t.Fatalf("服务器密码应为原文: %v", vps)
+	}
+}
+
+func TestMigrateRedactedSecrets(t *testing.T) {
+	ctx := context.Background()
+	src := newFakeStore()
+	src.put("downloader", map[string]interface{}{"id": "d1", "alias": "qb", "password": "qb-pw"})
+	src.put("downloader", map[string]interface{}{"id": "d2", "alias": "tr", "password": redactedValue})
+	srcClient, _ := newTestClient(t, src.routes())
+	dst := newFakeStore()
+	dstClient, _ := newTestClient(t, dst.routes())
+
+	if _, err := Migrate(ctx, srcClient, dstClient, MigrateOption{}); !errors.Is(err, ErrRedactedSecret) {
+		t.Fatalf("期望 ErrRedactedSecret，实际: %v", err)
+	}
+	if len(dst.bodies) != 0 {
+		t.Fatalf("拒绝迁移时不应修改目标实例: %v", dst.bodies)
+	}
+
+	res, err := Migrate(ctx, srcClient, dstClient, MigrateOption{SkipRedactedSecrets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Plan) != 1 || res.Plan[0].Alias != "qb" || len(res.Warnings) != 1 {
+		t.Fatalf("迁移结果不符合预期: %+v", res)
+	}
+	if dst.find("downloader", "tr") != nil || dst.find("downloader", "qb") == nil {
+		t.Fatal("应只迁移密码完整的下载器")
+	}
+}
+
+func TestAddRefusesRedactedSecret(t *testing.T) {
+	store := newFakeStore()
+	c, srv := newTestClient(t, store.routes())
+	ctx := context.Background()
+	redacted := Secret(redactedValue)
+
+	errs := []error{
+		c.AddServer(ctx, Server{Alias: "vps", Password: redacted}),
+		c.ModifyServer(ctx, Server{ID: "s1", Alias: "vps", Password: redacted}),
+		c.AddDownloader(ctx, DownloaderConfig{Alias: "qb", Password: redacted}),
+		c.ModifyDownloader(ctx, DownloaderConfig{ID: "d1", Alias: "qb", Password: redacted}),
+	}
+	for i, err := range errs {
+		if !errors.Is(err, ErrRedactedSecret) {
+			t.Errorf("第 %d 个调用期望 ErrRedactedSecret，实际: %v", i, err)
+		}
+	}
+	for _, path := range []string{"/api/server/add", "/api/server/modify", "/api/downloader/add", "/api/downloader/modify"} {
+		if n := srv.Calls(path); n != 0 {
+			t.Errorf("%s 不应被请求，实际 %d 次", path, n)
+		}
+	}
+}
+
+func TestWriteBackupRedacts(t *testing.T) {
+	a := &BackupArchive{Version: BackupVersion, Servers: []Server{{Alias: "vps", Password: "root-pw"}}}
+	var buf bytes.Buffer
+	if err := WriteBackup(&buf, a); err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Servers []map[string]interface{} `json:"servers"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw.Servers[0]["password"] != redactedValue {
+		t.Fatalf("默认备份中的密码应脱敏: %v", raw.Servers[0])
+	}
+
+	buf.Reset()
+	if err := WriteBackup(&buf, a, WithPlaintextSecrets()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadBackup(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Servers[0].Password.Reveal() != "root-pw" {
+		t.Fatalf("明文备份应保留原文: %q", got.Servers[0].Password.Reveal())
+	}
+	if _, err := ReadBackup(strings.NewReader(fmt.Sprintf(`{"version": %d}`, BackupVersion+1))); err == nil {
+		t.Fatal("期望不支持的版本错误")
+	}
+}
+
+func TestSelectMigration(t *testing.T) {
+	full := &BackupArchive{
+		Downloaders: []DownloaderConfig{
+			{ID: "d1", SameServerClients: []string{"d2"}, DeleteRules: []string{"x1"}},
+			{ID: "d2", SameServerClients: []string{"d3"}, RejectDeleteRules: []string{"x2"}},
+			{ID: "d3"},
+			{ID: "d4", DeleteRules: []string{"x3"}},
+		},
+		Rss: []RssConfig{
+			{ID: "r1", Client: "d1", AcceptRules: []string{"a1"}, RejectRules: []string{"a2"}},
+			{ID: "r2", Client: "d4"},
+		},
+		RssRules:    []RssRule{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}},
+		DeleteRules: []DeleteRule{{ID: "x1"}, {ID: "x2"}, {ID: "x3"}},
+	}
+	ids := func(a *BackupArchive) string {
+		var out []string
+		for _, d := range a.Downloaders {
+			out = append(out, d.ID)
+		}
+		for _, r := range a.Rss {
+			out = append(out, r.ID)
+		}
+		for _, r := range a.RssRules {
+			out = append(out, r.ID)
+		}
+		for _, r := range a.DeleteRules {
+			out = append(out, r.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name string
+		opt  MigrateOption
+		want string
+	}{
+		{"未选择时迁移全部", MigrateOption{}, "d1,d2,d3,d4,r1,r2,a1,a2,a3,x1,x2,x3"},
+		// r1 -> d1 -> d2 -> d3 (同服务器下载器链)，以及它们引用的规则
+		{"RSS 任务的依赖闭包", MigrateOption{Rss: []string{"r1"}}, "d1,d2,d3,r1,a1,a2,x1,x2"},
+		{"下载器的依赖闭包", MigrateOption{Downloaders: []string{"d2"}}, "d2,d3,x2"},
+		{"规则没有依赖", MigrateOption{RssRules: []string{"a3"}, DeleteRules: []string{"x3"}}, "a3,x3"},
+	}
+	for _, tt := range tests {
+		if got := ids(selectMigration(full, tt.opt)); got != tt.want {
+			t.Errorf("%s: 选中 %s, 期望 %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// migrationSource 包含删种规则、引用该规则的下载器与使用该下载器的 RSS 任务
+func migrationSource() *fakeStore {
+	src := newFakeStore()
+	src.put("deleteRule", map[string]interface{}{"id": "x1", "alias": "clean"})
+	src.put("downloader", map[string]interface{}{"id": "d1", "alias": "qb", "password": "qb-pw", "deleteRules": []string{"x1"}})
+	src.put("rss", map[string]interface{}{"id": "r1", "alias": "feed", "client": "d1"})
+	return src
+}
+
+// planActions 以 "别名=动作" 的形式输出计划
+func planActions(p MigratePlan) string {
+	var out []string
+	for _, s := range p {
+		out = append(out, s.TargetAlias+"="+string(s.Action))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	ctx := context.Background()
+	srcClient, _ := newTestClient(t, migrationSource().routes())
+	dst := newFakeStore()
+	dst.put("downloader", map[string]interface{}{"id": "existing", "alias": "qb"})
+	dstClient, _ := newTestClient(t, dst.routes())
+	progressFile := filepath.Join(t.TempDir(), "progress.json")
+
+	res, err := Migrate(ctx, srcClient, dstClient, MigrateOption{DryRun: true, ProgressFile: progressFile})
+	if !errors.Is(err, ErrMigrateConflict) {
+		t.Fatalf("期望 ErrMigrateConflict，实际: %v", err)
+	}
+	if got := planActions(res.Plan); got != "clean=create,qb=conflict,feed=create" {
+		t.Fatalf("计划: %s", got)
+	}
+
+	res, err = Migrate(ctx, srcClient, dstClient, MigrateOption{DryRun: true, OnCollision: CollisionRename, ProgressFile: progressFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := planActions(res.Plan); got != "clean=create,qb (迁移)=rename,feed=create" {
+		t.Fatalf("计划: %s", got)
+	}
+	if !strings.Contains(res.Plan.String(), "qb (d1) -> qb (迁移)") {
+		t.Fatalf("计划输出不符合预期:\n%s", res.Plan)
+	}
+	if len(dst.bodies) != 0 {
+		t.Fatalf("DryRun 不应修改目标实例: %v", dst.bodies)
+	}
+	if _, err := os.Stat(progressFile); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("DryRun 不应写入进度文件: %v", err)
+	}
+}
+
+func TestMigrateCollisionReuse(t *testing.T) {
+	ctx := context.Background()
+	srcClient, _ := newTestClient(t, migrationSource().routes())
+	dst := newFakeStore()
+	dst.put("downloader", map[string]interface{}{"id": "existing", "alias": "qb"})
+	dstClient, _ := newTestClient(t, dst.routes())
+
+	res, err := Migrate(ctx, srcClient, dstClient, MigrateOption{OnCollision: CollisionReuse})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := planActions(res.Plan); got != "clean=create,qb=reuse,feed=create" {
+		t.Fatalf("计划: %s", got)
+	}
+	if res.Created != 2 || res.Reused != 1 || res.IDs.Downloaders["d1"] != "existing" {
+		t.Fatalf("迁移结果不符合预期: %+v", res.RestoreResult)
+	}
+	if feed := dst.find("rss", "feed"); feed == nil || feed["client"] != "existing" {
+		t.Fatalf("RSS 任务应引用复用的下载器: %v", feed)
+	}
+	// 复用的对象不会被修改
+	for _, b := range dst.bodies {
+		if b["id"] == "existing" || b["alias"] == "qb" {
+			t.Fatalf("不应修改复用的下载器: %v", b)
+		}
+	}
+}
+
+func TestMigrateCollisionRename(t *testing.T) {
+	ctx := context.Background()
+	srcClient, _ := newTestClient(t, migrationSource().routes())
+	dst := newFakeStore()
+	dst.put("downloader", map[string]interface{}{"id": "existing", "alias": "qb"})
+	dst.put("downloader", map[string]interface{}{"id": "existing-2", "alias": "qb (迁移)"})
+	dstClient, _ := newTestClient(t, dst.routes())
+
+	res, err := Migrate(ctx, srcClient, dstClient, MigrateOption{OnCollision: CollisionRename})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := planActions(res.Plan); got != "clean=create,qb (迁移) 2=rename,feed=create" {
+		t.Fatalf("计划: %s", got)
+	}
+	qb := dst.find("downloader", "qb (迁移) 2")
+	if qb == nil || res.IDs.Downloaders["d1"] != qb["id"] {
+		t.Fatalf("应以新别名创建下载器: %v, %v", qb, res.IDs.Downloaders)
+	}
+	if rules, _ := qb["deleteRules"].([]interface{}); len(rules) != 1 || rules[0] != res.IDs.DeleteRules["x1"] {
+		t.Fatalf("删种规则引用应映射到新 ID: %v", qb["deleteRules"])
+	}
+	if feed := dst.find("rss", "feed"); feed == nil || feed["client"] != qb["id"] {
+		t.Fatalf("RSS 任务应引用改名后的下载器: %v", feed)
+	}
+}
+
+func TestMigrateResume(t *testing.T) {
+	ctx := context.Background()
+	src := migrationSource()
+	src.put("downloader", map[string]interface{}{"id": "d2", "alias": "tr", "password": "tr-pw"})
+	srcClient, _ := newTestClient(t, src.routes())
+
+	// tr 创建成功后，紧接着的一次下载器列表读取失败，无法确认其新 ID
+	dst := newFakeStore()
+	routes := dst.routes()
+	list := routes["/api/downloader/list"]
+	var failed atomic.Bool
+	routes["/api/downloader/list"] = func(r *http.Request) (interface{}, error) {
+		if dst.find("downloader", "tr") != nil && failed.CompareAndSwap(false, true) {
+			return nil, errors.New("模拟读取失败")
+		}
+		return list(r)
+	}
+	dstClient, _ := newTestClient(t, routes)
+	progressFile := filepath.Join(t.TempDir(), "progress.json")
+	opt := MigrateOption{ProgressFile: progressFile}
+
+	if _, err := Migrate(ctx, srcClient, dstClient, opt); err == nil {
+		t.Fatal("期望首次迁移失败")
+	}
+	progress, err := loadMigrateProgress(progressFile, srcClient.BaseURL, dstClient.BaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := progress.Pending; p == nil || p.SourceID != "d2" || p.Alias != "tr" {
+		t.Fatalf("进度文件应记录进行中的创建: %+v", p)
+	}
+
+	// DryRun 读取进度文件：已完成与已创建但未确认的对象都记为 done
+	dryRun := opt
+	dryRun.DryRun = true
+	res, err := Migrate(ctx, srcClient, dstClient, dryRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := planActions(res.Plan); got != "clean=done,qb=done,tr=done,feed=create" {
+		t.Fatalf("计划: %s", got)
+	}
+	created := len(dst.bodies)
+
+	// CollisionFail 下续传不应把上次创建的 tr 视为冲突
+	if res, err = Migrate(ctx, srcClient, dstClient, opt); err != nil {
+		t.Fatal(err)
+	}
+	if len(dst.bodies) != created+1 || dst.find("rss", "feed") == nil {
+		t.Fatalf("续传应只创建剩余的 RSS 任务: %v", dst.bodies[created:])
+	}
+	tr := dst.find("downloader", "tr")
+	if tr == nil || res.IDs.Downloaders["d2"] != tr["id"] {
+		t.Fatalf("应沿用上次创建的下载器: %v, %v", tr, res.IDs.Downloaders)
+	}
+	if progress, err = loadMigrateProgress(progressFile, srcClient.BaseURL, dstClient.BaseURL); err != nil {
+		t.Fatal(err)
+	}
+	if progress.Pending != nil || len(progress.IDs.Rss) != 1 || len(progress.IDs.Downloaders) != 2 {
+		t.Fatalf("完成后的进度不符合预期: %+v", progress)
+	}
+}
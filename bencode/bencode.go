@@ -0,0 +1,303 @@
+// Package bencode 实现 BitTorrent 使用的 bencode 编解码。
+//
+// 解码结果使用以下 Go 类型表示：
+//
+//	整数 -> int64
+//	字符串 -> string (可能包含任意二进制数据)
+//	列表 -> []interface{}
+//	字典 -> map[string]interface{}
+package bencode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// maxDepth 嵌套层数上限，防止恶意数据导致栈溢出
+const maxDepth = 256
+
+// SyntaxError 表示 bencode 数据格式错误
+type SyntaxError struct {
+	Offset int    // 出错位置
+	Msg    string // 错误描述
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("bencode 格式错误 (位置 %d): %s", e.Offset, e.Msg)
+}
+
+// Decode 解码一个完整的 bencode 值，末尾存在多余数据时返回错误
+func Decode(data []byte) (interface{}, error) {
+	d := decoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, &SyntaxError{d.pos, "值之后存在多余数据"}
+	}
+	return v, nil
+}
+
+// DecodeReader 读取 r 的全部内容并解码
+func DecodeReader(r io.Reader) (interface{}, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// RawDict 解析顶层字典，返回每个键对应值的原始字节 (不解码)。
+// 用于需要保留原始编码的场景，如计算 info 字典的 InfoHash。
+func RawDict(data []byte) (map[string][]byte, error) {
+	d := decoder{data: data}
+	if !d.peek('d') {
+		return nil, &SyntaxError{0, "顶层不是字典"}
+	}
+	d.pos++
+	out := make(map[string][]byte)
+	for !d.peek('e') {
+		key, err := d.str()
+		if err != nil {
+			return nil, err
+		}
+		start := d.pos
+		if err := d.skip(0); err != nil {
+			return nil, err
+		}
+		out[key] = data[start:d.pos]
+	}
+	d.pos++
+	if d.pos != len(data) {
+		return nil, &SyntaxError{d.pos, "值之后存在多余数据"}
+	}
+	return out, nil
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) peek(c byte) bool {
+	return d.pos < len(d.data) && d.data[d.pos] == c
+}
+
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, &SyntaxError{d.pos, "嵌套层数过深"}
+	}
+	if d.pos >= len(d.data) {
+		return nil, &SyntaxError{d.pos, "数据意外结束"}
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.int()
+	case c == 'l':
+		d.pos++
+		list := []interface{}{}
+		for !d.peek('e') {
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		d.pos++
+		return list, nil
+	case c == 'd':
+		d.pos++
+		dict := make(map[string]interface{})
+		for !d.peek('e') {
+			key, err := d.str()
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = v
+		}
+		d.pos++
+		return dict, nil
+	case c >= '0' && c <= '9':
+		return d.str()
+	default:
+		return nil, &SyntaxError{d.pos, fmt.Sprintf("无效的类型标记 %q", c)}
+	}
+}
+
+// skip 跳过一个完整的值，不分配内存
+func (d *decoder) skip(depth int) error {
+	if depth > maxDepth {
+		return &SyntaxError{d.pos, "嵌套层数过深"}
+	}
+	if d.pos >= len(d.data) {
+		return &SyntaxError{d.pos, "数据意外结束"}
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		_, err := d.int()
+		return err
+	case c == 'l' || c == 'd':
+		d.pos++
+		for !d.peek('e') {
+			if d.pos >= len(d.data) {
+				return &SyntaxError{d.pos, "列表或字典未结束"}
+			}
+			if c == 'd' {
+				if _, err := d.str(); err != nil {
+					return err
+				}
+			}
+			if err := d.skip(depth + 1); err != nil {
+				return err
+			}
+		}
+		d.pos++
+		return nil
+	default:
+		_, err := d.str()
+		return err
+	}
+}
+
+func (d *decoder) int() (int64, error) {
+	start := d.pos
+	end := bytes.IndexByte(d.data[start:], 'e')
+	if end < 0 {
+		return 0, &SyntaxError{start, "整数未结束"}
+	}
+	digits := d.data[start+1 : start+end]
+	if !canonicalInt(digits, true) {
+		return 0, &SyntaxError{start, "整数格式无效"}
+	}
+	n, err := strconv.ParseInt(string(digits), 10, 64)
+	if err != nil {
+		return 0, &SyntaxError{start, "整数格式无效"}
+	}
+	d.pos = start + end + 1
+	return n, nil
+}
+
+func (d *decoder) str() (string, error) {
+	if d.pos >= len(d.data) {
+		return "", &SyntaxError{d.pos, "列表或字典未结束"}
+	}
+	start := d.pos
+	colon := bytes.IndexByte(d.data[start:], ':')
+	if colon <= 0 || !canonicalInt(d.data[start:start+colon], false) {
+		return "", &SyntaxError{start, "字符串格式无效"}
+	}
+	n, err := strconv.Atoi(string(d.data[start : start+colon]))
+	// 与剩余长度比较，避免 start+colon+1+n 在 n 很大时溢出
+	if err != nil || n > len(d.data)-(start+colon+1) {
+		return "", &SyntaxError{start, "字符串长度无效"}
+	}
+	d.pos = start + colon + 1 + n
+	return string(d.data[start+colon+1 : d.pos]), nil
+}
+
+// canonicalInt 检查整数是否为规范写法：只含十进制数字，不带 "+"，除 0 本身外没有前导零，不允许 "-0"。
+// 同一个值只有一种编码，才能保证重新编码后 InfoHash 不变。
+func canonicalInt(b []byte, allowNegative bool) bool {
+	if allowNegative && len(b) > 0 && b[0] == '-' {
+		b = b[1:]
+		if len(b) > 0 && b[0] == '0' {
+			return false
+		}
+	}
+	if len(b) == 0 || (b[0] == '0' && len(b) > 1) {
+		return false
+	}
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode 将值编码为 bencode。支持的类型：整数、string、[]byte、bool (编码为 0/1)、
+// []interface{}、[]string、map[string]interface{}；字典的键按字节序排序。
+func Encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch x := v.(type) {
+	case int:
+		writeInt(buf, int64(x))
+	case int32:
+		writeInt(buf, int64(x))
+	case int64:
+		writeInt(buf, x)
+	case uint32:
+		writeInt(buf, int64(x))
+	case bool:
+		if x {
+			writeInt(buf, 1)
+		} else {
+			writeInt(buf, 0)
+		}
+	case string:
+		writeString(buf, x)
+	case []byte:
+		writeString(buf, string(x))
+	case []string:
+		buf.WriteByte('l')
+		for _, s := range x {
+			writeString(buf, s)
+		}
+		buf.WriteByte('e')
+	case []interface{}:
+		buf.WriteByte('l')
+		for _, item := range x {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('d')
+		for _, k := range keys {
+			writeString(buf, k)
+			if err := encode(buf, x[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	case nil:
+		return errors.New("bencode 不支持 nil 值")
+	default:
+		return fmt.Errorf("bencode 不支持的类型: %T", v)
+	}
+	return nil
+}
+
+func writeInt(buf *bytes.Buffer, n int64) {
+	buf.WriteByte('i')
+	buf.WriteString(strconv.FormatInt(n, 10))
+	buf.WriteByte('e')
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteString(strconv.Itoa(len(s)))
+	buf.WriteByte(':')
+	buf.WriteString(s)
+}
@@ -0,0 +1,160 @@
+package bencode
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{"i0e", int64(0)},
+		{"i42e", int64(42)},
+		{"i-42e", int64(-42)},
+		{"i9223372036854775807e", int64(9223372036854775807)},
+		{"0:", ""},
+		{"4:spam", "spam"},
+		{"le", []interface{}{}},
+		{"l4:spami1ee", []interface{}{"spam", int64(1)}},
+		{"de", map[string]interface{}{}},
+		{"d3:bar4:spam3:fooi42ee", map[string]interface{}{"bar": "spam", "foo": int64(42)}},
+		{"d1:ald1:bi1eeee", map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": int64(1)}}}},
+	}
+	for _, tt := range tests {
+		got, err := Decode([]byte(tt.in))
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Decode(%q) = %#v, %v, 期望 %#v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name, in string
+	}{
+		{"空输入", ""},
+		{"整数前导零", "i03e"},
+		{"负零", "i-0e"},
+		{"负数前导零", "i-01e"},
+		{"整数带加号", "i+5e"},
+		{"空整数", "ie"},
+		{"只有负号", "i-e"},
+		{"整数溢出", "i9223372036854775808e"},
+		{"整数未结束", "i42"},
+		{"长度带加号", "+5:hello"},
+		{"长度前导零", "05:hello"},
+		{"长度为负", "-1:a"},
+		{"长度超过剩余数据", "5:abc"},
+		{"长度溢出", "9223372036854775807:abc"},
+		{"长度超出 int64", "99999999999999999999:abc"},
+		{"缺少冒号", "5abc"},
+		{"列表未结束", "l4:spam"},
+		{"字典未结束", "d3:foo"},
+		{"字典键不是字符串", "di1ei2ee"},
+		{"无效类型标记", "x"},
+		{"多余数据", "i1ei2e"},
+		{"嵌套过深", string(bytes.Repeat([]byte("l"), maxDepth+2)) + string(bytes.Repeat([]byte("e"), maxDepth+2))},
+	}
+	for _, tt := range tests {
+		_, err := Decode([]byte(tt.in))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: Decode(%.40q) 错误 = %v, 期望 SyntaxError", tt.name, tt.in, err)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// 规范编码 (字典键有序、整数与长度无前导零) 解码后重新编码应得到相同的字节
+	for _, in := range []string{
+		"i0e",
+		"i-7e",
+		"3:\x00\xff\n",
+		"l1:ai1eli2eee",
+		"d4:infod6:lengthi1024e4:name4:test12:piece lengthi16384ee4:listl1:x1:yee",
+	} {
+		v, err := Decode([]byte(in))
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", in, err)
+		}
+		out, err := Encode(v)
+		if err != nil || string(out) != in {
+			t.Errorf("Encode(Decode(%q)) = %q, %v", in, out, err)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	got, err := Encode(map[string]interface{}{
+		"b":     true,
+		"a":     []string{"x"},
+		"bytes": []byte("hi"),
+		"n":     int32(-3),
+	})
+	if err != nil || string(got) != "d1:al1:xe1:bi1e5:bytes2:hi1:ni-3ee" {
+		t.Errorf("Encode = %q, %v", got, err)
+	}
+	if _, err := Encode(nil); err == nil {
+		t.Error("Encode(nil) 期望返回错误")
+	}
+	if _, err := Encode(3.14); err == nil {
+		t.Error("Encode(float64) 期望返回错误")
+	}
+}
+
+func TestRawDict(t *testing.T) {
+	// info 的键未排序，RawDict 必须原样返回其字节
+	in := "d8:announce1:a4:infod4:name1:x6:lengthi1eee"
+	raw, err := RawDict([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw["info"]) != "d4:name1:x6:lengthi1ee" || string(raw["announce"]) != "1:a" {
+		t.Errorf("RawDict = %q", raw)
+	}
+	for _, bad := range []string{"l1:ae", "d4:info", "d4:infoi03ee", "d1:ai1eetrailing"} {
+		if _, err := RawDict([]byte(bad)); err == nil {
+			t.Errorf("RawDict(%q) 期望返回错误", bad)
+		}
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	for _, seed := range []string{
+		"i42e", "i-0e", "4:spam", "le", "d3:foo3:bare", "d4:infod6:lengthi1e4:name1:aee",
+		"9223372036854775807:", "l" + string(bytes.Repeat([]byte("l"), 300)),
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := Decode(data)
+		if _, rawErr := RawDict(data); rawErr == nil {
+			if _, ok := v.(map[string]interface{}); !ok || err != nil {
+				t.Fatalf("RawDict 成功但 Decode 失败: %v", err)
+			}
+		}
+		if err != nil {
+			return
+		}
+		// 能解码的数据重新编码后必须得到相同的值，且重新编码的结果是规范编码 (再次编码不变)
+		out, err := Encode(v)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		v2, err := Decode(out)
+		if err != nil {
+			t.Fatalf("重新解码失败: %v", err)
+		}
+		if !reflect.DeepEqual(v, v2) {
+			t.Fatalf("往返结果不一致: %#v != %#v", v, v2)
+		}
+		out2, _ := Encode(v2)
+		if !bytes.Equal(out, out2) {
+			t.Fatalf("编码不稳定: %q != %q", out, out2)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ==========================================
+// 批量查询 (Bulk)
+// ==========================================
+
+// BulkResult 批量查询的结果，成功与失败的条目分别以传入的 ID (或 hash) 为键
+type BulkResult[T any] struct {
+	Values map[string]T     // 成功条目的结果
+	Errors map[string]error // 失败条目的错误，ctx 取消后尚未执行的条目记录为 ctx.Err()
+}
+
+// Err 将所有失败条目的错误合并为一个错误，全部成功时返回 nil
+func (r *BulkResult[T]) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(r.Errors))
+	for key := range r.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		errs = append(errs, fmt.Errorf("%s: %w", key, r.Errors[key]))
+	}
+	return errors.Join(errs...)
+}
+
+// BulkFetch 以最多 limit 个并发 (<=0 时使用默认值 4) 对每个 key 执行 fn，
+// 重复的 key 只查询一次。ctx 取消后不再发起新的查询。
+func BulkFetch[T any](ctx context.Context, keys []string, limit int, fn func(ctx context.Context, key string) (T, error)) *BulkResult[T] {
+	if limit <= 0 {
+		limit = batchConcurrency
+	}
+	seen := make(map[string]bool, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+
+	res := &BulkResult[T]{
+		Values: make(map[string]T),
+		Errors: make(map[string]error),
+	}
+	var mu sync.Mutex
+	parallel(len(unique), limit, func(i int) {
+		var (
+			v   T
+			err = ctx.Err()
+		)
+		if err == nil {
+			v, err = fn(ctx, unique[i])
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			res.Errors[unique[i]] = err
+			return
+		}
+		res.Values[unique[i]] = v
+	})
+	return res
+}
+
+// GetServersVnstat 并发获取多台服务器的 Vnstat 流量统计，以服务器 ID 为键
+func (c *Client) GetServersVnstat(ctx context.Context, serverIDs []string) *BulkResult[*VnstatInfo] {
+	return BulkFetch(ctx, serverIDs, batchConcurrency, c.GetServerVnstat)
+}
+
+// GetTorrentsInfo 并发获取多个种子的详细信息，以种子 hash 为键
+func (c *Client) GetTorrentsInfo(ctx context.Context, hashes []string) *BulkResult[*Torrent] {
+	return BulkFetch(ctx, hashes, batchConcurrency, c.GetTorrentInfo)
+}
+
+// GetAllServersVnstat 获取全部服务器的 Vnstat 流量统计
+func (c *Client) GetAllServersVnstat(ctx context.Context) (*BulkResult[*VnstatInfo], error) {
+	servers, err := c.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(servers))
+	for i, s := range servers {
+		ids[i] = s.ID
+	}
+	return c.GetServersVnstat(ctx, ids), nil
+}
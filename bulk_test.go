@@ -0,0 +1,157 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBulkFetchDedup(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	res := BulkFetch(context.Background(), []string{"a", "b", "a", "c", "b", "a"}, 2, func(ctx context.Context, key string) (string, error) {
+		mu.Lock()
+		calls[key]++
+		mu.Unlock()
+		if key == "c" {
+			return "", errors.New("失败")
+		}
+		return strings.ToUpper(key), nil
+	})
+
+	for _, key := range []string{"a", "b", "c"} {
+		if calls[key] != 1 {
+			t.Errorf("%s 应只查询一次，实际 %d 次", key, calls[key])
+		}
+	}
+	if len(res.Values) != 2 || res.Values["a"] != "A" || res.Values["b"] != "B" {
+		t.Fatalf("成功结果不符合预期: %v", res.Values)
+	}
+	if len(res.Errors) != 1 || res.Errors["c"] == nil {
+		t.Fatalf("失败结果不符合预期: %v", res.Errors)
+	}
+}
+
+func TestBulkFetchConcurrencyLimit(t *testing.T) {
+	keys := make([]string, 20)
+	for i := range keys {
+		keys[i] = fmt.Sprint(i)
+	}
+	for _, tt := range []struct {
+		limit, want int
+	}{
+		{3, 3},
+		{0, batchConcurrency}, // 未设置时使用默认并发数
+		{100, len(keys)},
+	} {
+		var inFlight, peak int32
+		BulkFetch(context.Background(), keys, tt.limit, func(ctx context.Context, key string) (int, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return 0, nil
+		})
+		if int(peak) > tt.want {
+			t.Errorf("limit=%d: 最大并发 %d 超过 %d", tt.limit, peak, tt.want)
+		}
+		if peak < 2 {
+			t.Errorf("limit=%d: 查询没有并发执行", tt.limit)
+		}
+	}
+}
+
+func TestBulkFetchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var called []string
+	keys := []string{"a", "b", "c", "d"}
+	res := BulkFetch(ctx, keys, 1, func(ctx context.Context, key string) (string, error) {
+		called = append(called, key)
+		if key == "b" {
+			cancel()
+			return "", ctx.Err()
+		}
+		return key, nil
+	})
+
+	// 并发数为 1 时按顺序执行，取消后不再调用 fn
+	if strings.Join(called, ",") != "a,b" {
+		t.Fatalf("取消后不应继续查询，实际调用: %v", called)
+	}
+	if len(res.Values) != 1 || res.Values["a"] != "a" {
+		t.Fatalf("成功结果不符合预期: %v", res.Values)
+	}
+	for _, key := range []string{"b", "c", "d"} {
+		if !errors.Is(res.Errors[key], context.Canceled) {
+			t.Errorf("%s 应记录为取消错误，实际: %v", key, res.Errors[key])
+		}
+	}
+	if !errors.Is(res.Err(), context.Canceled) {
+		t.Fatalf("合并后的错误应包含取消错误: %v", res.Err())
+	}
+
+	// 已取消的 ctx 不发起任何查询
+	res = BulkFetch(ctx, keys, 2, func(ctx context.Context, key string) (string, error) {
+		t.Errorf("不应查询 %s", key)
+		return "", nil
+	})
+	if len(res.Errors) != len(keys) {
+		t.Fatalf("所有条目都应记录取消错误: %v", res.Errors)
+	}
+}
+
+func TestBulkResultErr(t *testing.T) {
+	res := &BulkResult[int]{Values: map[string]int{"ok": 1}, Errors: map[string]error{}}
+	if res.Err() != nil {
+		t.Fatalf("全部成功时应返回 nil: %v", res.Err())
+	}
+
+	errB := errors.New("b 失败")
+	res.Errors["b"] = errB
+	res.Errors["a"] = errors.New("a 失败")
+	err := res.Err()
+	if !errors.Is(err, errB) {
+		t.Fatalf("合并后的错误应包含各条目的错误: %v", err)
+	}
+	// 按 key 排序，输出稳定
+	if want := "a: a 失败\nb: b 失败"; err.Error() != want {
+		t.Fatalf("错误信息为 %q, 期望 %q", err.Error(), want)
+	}
+}
+
+func TestGetTorrentsInfo(t *testing.T) {
+	c, srv := newTestClient(t, map[string]testRoute{
+		"/api/torrent/info": func(r *http.Request) (interface{}, error) {
+			hash := r.URL.Query().Get("hash")
+			if hash == "missing" {
+				return nil, errors.New("种子不存在")
+			}
+			return map[string]interface{}{"hash": hash, "name": "torrent-" + hash}, nil
+		},
+	})
+
+	res := c.GetTorrentsInfo(context.Background(), []string{"h1", "h2", "h1", "missing"})
+	if n := srv.Calls("/api/torrent/info"); n != 3 {
+		t.Fatalf("重复的 hash 应只请求一次，实际请求 %d 次", n)
+	}
+	if len(res.Values) != 2 || res.Values["h2"].Name != "torrent-h2" {
+		t.Fatalf("成功结果不符合预期: %v", res.Values)
+	}
+	var apiErr *APIError
+	if !errors.As(res.Errors["missing"], &apiErr) {
+		t.Fatalf("失败条目应保留业务错误，实际: %v", res.Errors["missing"])
+	}
+}
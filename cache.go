@@ -0,0 +1,252 @@
+package vertex
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// 响应缓存 (Response Cache)
+// ==========================================
+
+// defaultCachedEndpoints WithCache 默认缓存的列表接口
+var defaultCachedEndpoints = []string{
+	"/api/server/list",
+	"/api/downloader/list",
+	"/api/rss/list",
+	"/api/rssRule/list",
+	"/api/deleteRule/list",
+}
+
+// maxCacheEntries 缓存条目上限，超过时淘汰最久未使用的条目
+const maxCacheEntries = 1024
+
+// WithCache 为服务器、下载器、RSS 任务与规则的列表接口开启响应缓存，缓存有效期为 ttl。
+// 同一资源下的写操作 (如 AddRss、ModifyDownloader) 成功后会自动清除对应缓存。
+func WithCache(ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		for _, path := range defaultCachedEndpoints {
+			c.cache.setTTL(path, ttl)
+		}
+		return nil
+	}
+}
+
+// WithEndpointCache 为指定 GET 接口 (如 "/api/torrent/list") 单独配置缓存有效期，ttl <= 0 表示不缓存。
+// 带参数的请求按参数分别缓存。
+func WithEndpointCache(path string, ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.cache.setTTL(path, ttl)
+		return nil
+	}
+}
+
+// InvalidateCache 清除路径以任一 prefix 开头 (如 "/api/rss/") 的缓存，不传参数时清除全部缓存
+func (c *Client) InvalidateCache(prefixes ...string) {
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	c.cache.invalidate(prefixes...)
+}
+
+// responseCache 按接口配置有效期的响应缓存，并合并并发的相同请求
+type responseCache struct {
+	mu      sync.Mutex
+	ttls    map[string]time.Duration
+	entries map[string]*list.Element // 值为 *cacheEntry
+	lru     list.List                // 最近使用的条目在前
+	flights map[string]*cacheFlight
+	gen     uint64 // 每次清除缓存时递增，用于丢弃清除前发起的请求结果
+}
+
+type cacheEntry struct {
+	key     string
+	resp    *Response
+	expires time.Time
+}
+
+// cacheFlight 进行中的请求，相同请求的调用方等待其结果
+type cacheFlight struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+func (rc *responseCache) setTTL(path string, ttl time.Duration) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.ttls == nil {
+		rc.ttls = make(map[string]time.Duration)
+	}
+	if ttl <= 0 {
+		delete(rc.ttls, path)
+		return
+	}
+	rc.ttls[path] = ttl
+}
+
+// ttl 返回请求的缓存有效期，0 表示不缓存 (只缓存 GET 请求)
+func (rc *responseCache) ttl(method, path string) time.Duration {
+	if method != "GET" {
+		return 0
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.ttls[path]
+}
+
+// do 返回缓存的响应；未命中时执行 fetch，同一时刻相同的请求只会执行一次。
+// shared 表示结果来自缓存或其他调用方发起的相同请求，本次调用没有执行 fetch。
+func (rc *responseCache) do(ctx context.Context, path string, params map[string]string, ttl time.Duration, fetch func() (*Response, error)) (resp *Response, shared bool, err error) {
+	key := cacheKey(path, params)
+	for {
+		rc.mu.Lock()
+		if e := rc.lookup(key); e != nil {
+			rc.mu.Unlock()
+			return e.resp.clone(), true, nil
+		}
+		if f, ok := rc.flights[key]; ok {
+			rc.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, false, ctx.Err()
+			}
+			// 发起请求的调用方被取消时，由仍然有效的调用方重新请求
+			if isContextError(f.err) && ctx.Err() == nil {
+				continue
+			}
+			return f.resp.clone(), true, f.err
+		}
+
+		f := &cacheFlight{done: make(chan struct{})}
+		if rc.flights == nil {
+			rc.flights = make(map[string]*cacheFlight)
+		}
+		rc.flights[key] = f
+		gen := rc.gen
+		rc.mu.Unlock()
+
+		f.resp, f.err = fetch()
+
+		rc.mu.Lock()
+		if rc.flights[key] == f {
+			delete(rc.flights, key)
+		}
+		if f.err == nil && rc.gen == gen {
+			rc.store(&cacheEntry{key: key, resp: f.resp, expires: time.Now().Add(ttl)})
+		}
+		rc.mu.Unlock()
+		close(f.done)
+		return f.resp.clone(), false, f.err
+	}
+}
+
+// lookup 返回未过期的缓存条目并标记为最近使用，过期的条目会被删除。调用方需持有锁
+func (rc *responseCache) lookup(key string) *cacheEntry {
+	el, ok := rc.entries[key]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*cacheEntry)
+	if !time.Now().Before(e.expires) {
+		rc.remove(el)
+		return nil
+	}
+	rc.lru.MoveToFront(el)
+	return e
+}
+
+// store 写入缓存，超过 maxCacheEntries 时淘汰最久未使用的条目。调用方需持有锁
+func (rc *responseCache) store(e *cacheEntry) {
+	if rc.entries == nil {
+		rc.entries = make(map[string]*list.Element)
+	}
+	if el, ok := rc.entries[e.key]; ok {
+		el.Value = e
+		rc.lru.MoveToFront(el)
+		return
+	}
+	rc.entries[e.key] = rc.lru.PushFront(e)
+	for len(rc.entries) > maxCacheEntries {
+		rc.remove(rc.lru.Back())
+	}
+}
+
+// remove 删除缓存条目，调用方需持有锁
+func (rc *responseCache) remove(el *list.Element) {
+	rc.lru.Remove(el)
+	delete(rc.entries, el.Value.(*cacheEntry).key)
+}
+
+// invalidate 清除以任一前缀开头的缓存与进行中的请求 (之后的调用会重新请求)
+func (rc *responseCache) invalidate(prefixes ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.gen++
+	for key, el := range rc.entries {
+		if hasAnyPrefix(key, prefixes) {
+			rc.remove(el)
+		}
+	}
+	for key := range rc.flights {
+		if hasAnyPrefix(key, prefixes) {
+			delete(rc.flights, key)
+		}
+	}
+}
+
+// invalidateAfter 写操作成功后清除同一资源下的缓存，如 /api/rss/add 清除 /api/rss/list
+func (rc *responseCache) invalidateAfter(method, path string) {
+	if method == "GET" {
+		return
+	}
+	rc.mu.Lock()
+	empty := len(rc.entries) == 0 && len(rc.flights) == 0
+	rc.mu.Unlock()
+	if empty {
+		return
+	}
+	if i := strings.LastIndex(path, "/"); i > 0 {
+		rc.invalidate(path[:i+1])
+	}
+}
+
+func cacheKey(path string, params map[string]string) string {
+	if len(params) == 0 {
+		return path
+	}
+	values := make(url.Values, len(params))
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return path + "?" + values.Encode()
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// clone 复制响应，避免调用方修改共享的缓存内容
+func (r *Response) clone() *Response {
+	if r == nil {
+		return nil
+	}
+	cp := *r
+	cp.Data = append([]byte(nil), r.Data...)
+	return &cp
+}
@@ -0,0 +1,326 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedRoute 在 gate 关闭前阻塞的接口，每次收到请求时向 started 发送一次
+func gatedRoute(started chan<- struct{}, gate <-chan struct{}, data interface{}) testRoute {
+	return func(r *http.Request) (interface{}, error) {
+		started <- struct{}{}
+		select {
+		case <-gate:
+			return data, nil
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+	}
+}
+
+// cacheHits 记录每次调用 Request.CacheHit 的中间件
+type cacheHits struct {
+	mu   sync.Mutex
+	hits []bool
+}
+
+func (h *cacheHits) middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			h.mu.Lock()
+			h.hits = append(h.hits, req.CacheHit)
+			h.mu.Unlock()
+			return next(ctx, req)
+		}
+	}
+}
+
+func (h *cacheHits) count() (hits, misses int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, hit := range h.hits {
+		if hit {
+			hits++
+		} else {
+			misses++
+		}
+	}
+	return hits, misses
+}
+
+var cachedServers = []map[string]interface{}{{"id": "s1", "alias": "vps"}}
+
+func TestCacheHitPassesMiddleware(t *testing.T) {
+	var recorder cacheHits
+	metrics := NewEndpointMetrics()
+	c, srv := newTestClient(t, map[string]testRoute{"/api/server/list": staticRoute(cachedServers)},
+		WithCache(time.Minute), WithMiddleware(recorder.middleware(), metrics.Middleware()))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		servers, err := c.ListServers(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(servers) != 1 || servers[0].Alias != "vps" {
+			t.Fatalf("缓存的响应不符合预期: %+v", servers)
+		}
+	}
+	if n := srv.Calls("/api/server/list"); n != 1 {
+		t.Fatalf("期望只请求 1 次，实际 %d 次", n)
+	}
+	if hits, misses := recorder.count(); hits != 2 || misses != 1 {
+		t.Fatalf("中间件应看到 2 次命中与 1 次未命中，实际 %d / %d", hits, misses)
+	}
+	if s := metrics.Snapshot()["/api/server/list"]; s.Count != 3 || s.CacheHits != 2 {
+		t.Fatalf("接口统计不符合预期: %+v", s)
+	}
+}
+
+func TestCacheCoalescing(t *testing.T) {
+	started, gate := make(chan struct{}, 10), make(chan struct{})
+	var recorder cacheHits
+	c, srv := newTestClient(t, map[string]testRoute{"/api/server/list": gatedRoute(started, gate, cachedServers)},
+		WithCache(time.Minute), WithMiddleware(recorder.middleware()))
+
+	const n = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			servers, err := c.ListServers(context.Background())
+			if err == nil && (len(servers) != 1 || servers[0].ID != "s1") {
+				err = fmt.Errorf("响应不符合预期: %+v", servers)
+			}
+			errs <- err
+		}()
+	}
+	<-started
+	time.Sleep(50 * time.Millisecond) // 等待其余调用方加入进行中的请求
+	close(gate)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls := srv.Calls("/api/server/list"); calls != 1 {
+		t.Fatalf("并发的相同请求应只发起 1 次，实际 %d 次", calls)
+	}
+	if hits, misses := recorder.count(); hits != n-1 || misses != 1 {
+		t.Fatalf("期望 %d 次共用结果与 1 次请求，实际 %d / %d", n-1, hits, misses)
+	}
+}
+
+func TestCacheInvalidateAfterWrite(t *testing.T) {
+	store := newFakeStore()
+	routes := store.routes()
+	c, srv := newTestClient(t, routes, WithCache(time.Minute))
+	ctx := context.Background()
+
+	list := func() []RssConfig {
+		t.Helper()
+		rss, err := c.ListRss(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rss
+	}
+	if len(list()) != 0 || len(list()) != 0 {
+		t.Fatal("初始应没有 RSS 任务")
+	}
+	if n := srv.Calls("/api/rss/list"); n != 1 {
+		t.Fatalf("第二次读取应命中缓存，实际请求 %d 次", n)
+	}
+
+	// 其他资源的写操作不清除 RSS 缓存
+	if err := c.AddRssRules(ctx, RssRule{Alias: "rule"}); err != nil {
+		t.Fatal(err)
+	}
+	list()
+	if n := srv.Calls("/api/rss/list"); n != 1 {
+		t.Fatalf("选种规则的写操作不应清除 RSS 缓存，实际请求 %d 次", n)
+	}
+
+	if err := c.AddRss(ctx, RssConfig{Alias: "feed"}); err != nil {
+		t.Fatal(err)
+	}
+	if rss := list(); len(rss) != 1 || rss[0].Alias != "feed" {
+		t.Fatalf("写操作后应读取到新数据: %+v", rss)
+	}
+	if n := srv.Calls("/api/rss/list"); n != 2 {
+		t.Fatalf("写操作后应重新请求，实际请求 %d 次", n)
+	}
+
+	// 失败的写操作不清除缓存
+	store.mu.Lock()
+	store.failOn = "bad"
+	store.mu.Unlock()
+	if err := c.AddRss(ctx, RssConfig{Alias: "bad"}); err == nil {
+		t.Fatal("期望写操作失败")
+	}
+	list()
+	if n := srv.Calls("/api/rss/list"); n != 2 {
+		t.Fatalf("失败的写操作不应清除缓存，实际请求 %d 次", n)
+	}
+}
+
+func TestCacheDiscardStaleResult(t *testing.T) {
+	started, gate := make(chan struct{}, 10), make(chan struct{})
+	first := true
+	var mu sync.Mutex
+	blocking := gatedRoute(started, gate, cachedServers)
+	c, srv := newTestClient(t, map[string]testRoute{"/api/server/list": func(r *http.Request) (interface{}, error) {
+		mu.Lock()
+		block := first
+		first = false
+		mu.Unlock()
+		if block {
+			return blocking(r)
+		}
+		return []map[string]interface{}{{"id": "s2", "alias": "new"}}, nil
+	}}, WithCache(time.Minute))
+	ctx := context.Background()
+
+	done := make(chan []Server)
+	go func() {
+		servers, _ := c.ListServers(ctx)
+		done <- servers
+	}()
+	<-started
+	// 请求进行中清除缓存，其结果已过时，不应写入缓存
+	c.InvalidateCache()
+	close(gate)
+	if servers := <-done; len(servers) != 1 || servers[0].ID != "s1" {
+		t.Fatalf("进行中的请求仍应返回自身的结果: %+v", servers)
+	}
+
+	servers, err := c.ListServers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 1 || servers[0].ID != "s2" {
+		t.Fatalf("清除前发起的请求结果不应被缓存: %+v", servers)
+	}
+	if n := srv.Calls("/api/server/list"); n != 2 {
+		t.Fatalf("期望请求 2 次，实际 %d 次", n)
+	}
+}
+
+func TestCacheLeaderCancelled(t *testing.T) {
+	started, gate := make(chan struct{}, 10), make(chan struct{})
+	var calls int32
+	blocking := gatedRoute(started, gate, nil)
+	c, _ := newTestClient(t, map[string]testRoute{"/api/server/list": func(r *http.Request) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return blocking(r)
+		}
+		return cachedServers, nil
+	}}, WithCache(time.Minute))
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.ListServers(leaderCtx)
+		leaderErr <- err
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		servers, err := c.ListServers(context.Background())
+		if err == nil && (len(servers) != 1 || servers[0].ID != "s1") {
+			err = fmt.Errorf("响应不符合预期: %+v", servers)
+		}
+		waiter <- err
+	}()
+	time.Sleep(50 * time.Millisecond) // 等待第二个调用方加入进行中的请求
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("被取消的调用方应返回取消错误，实际: %v", err)
+	}
+	// 发起请求的调用方被取消后，仍然有效的调用方重新请求而不是返回取消错误
+	if err := <-waiter; err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("期望请求 2 次，实际 %d 次", n)
+	}
+}
+
+func TestCacheWaiterCancelled(t *testing.T) {
+	started, gate := make(chan struct{}, 10), make(chan struct{})
+	c, _ := newTestClient(t, map[string]testRoute{"/api/server/list": gatedRoute(started, gate, cachedServers)},
+		WithCache(time.Minute))
+	defer close(gate)
+
+	go func() { _, _ = c.ListServers(context.Background()) }()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListServers(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("等待中的调用方超时应返回超时错误，实际: %v", err)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	c, srv := newTestClient(t, map[string]testRoute{"/api/server/list": staticRoute(cachedServers)},
+		WithCache(20*time.Millisecond))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListServers(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := c.ListServers(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.Calls("/api/server/list"); n != 2 {
+		t.Fatalf("过期后应重新请求，实际请求 %d 次", n)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var rc responseCache
+	ctx := context.Background()
+	fetches := make(map[string]int)
+	get := func(id string) {
+		t.Helper()
+		_, _, err := rc.do(ctx, "/api/torrent/info", map[string]string{"hash": id}, time.Minute, func() (*Response, error) {
+			fetches[id]++
+			return &Response{Success: true}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < maxCacheEntries; i++ {
+		get(fmt.Sprint(i))
+	}
+	get("0") // 最早写入的条目最近被使用，不应被淘汰
+	get("new")
+	if len(rc.entries) != maxCacheEntries || rc.lru.Len() != maxCacheEntries {
+		t.Fatalf("缓存条目数应为上限 %d，实际 %d / %d", maxCacheEntries, len(rc.entries), rc.lru.Len())
+	}
+	get("0")
+	get("1")
+	if fetches["0"] != 1 {
+		t.Fatalf("最近使用的条目不应被淘汰，实际请求 %d 次", fetches["0"])
+	}
+	if fetches["1"] != 2 {
+		t.Fatalf("最久未使用的条目应被淘汰，实际请求 %d 次", fetches["1"])
+	}
+}
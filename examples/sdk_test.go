@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/iniwex5/vertex-go-sdk" // 导入 SDK
+	"github.com/iniwex5/vertex-go-sdk/exporter"
 	"github.com/joho/godotenv"
 )
 
@@ -294,18 +295,44 @@ func TestTorrentManagement(t *testing.T) {
 		t.Logf("目标种子: %s", info.Name)
 	})
 
-	// 2. 软链接示例 (代码演示)
+	// 2. 文件与 Tracker 详情
+	t.Run("文件与Tracker", func(t *testing.T) {
+		target := result.Torrents[0]
+		files, err := client.GetTorrentFiles(ctx, target.Hash, target.ClientID)
+		if err != nil {
+			t.Skipf("下载器不支持获取文件列表: %v", err)
+		}
+		t.Logf("共 %d 个文件", len(files))
+
+		trackers, err := client.GetTorrentTrackers(ctx, target.Hash, target.ClientID)
+		if err != nil {
+			t.Skipf("下载器不支持获取 Tracker: %v", err)
+		}
+		for _, tr := range trackers {
+			t.Logf("Tracker: %s | 状态: %d | 信息: %s", tr.URL, tr.Status, tr.Message)
+		}
+	})
+
+	// 3. 软链接示例 (代码演示)
 	t.Run("链接操作演示", func(t *testing.T) {
 		t.Log("演示：通过 client.LinkTorrent(ctx, payload) 可执行链接操作")
 		// payload := map[string]interface{}{ ... }
 		// _ = client.LinkTorrent(ctx, payload)
 	})
 
-	// 3. 删除操作演示 (代码演示)
+	// 4. 删除操作演示 (代码演示)
 	t.Run("删除操作演示", func(t *testing.T) {
 		t.Log("演示：通过 client.DeleteTorrent(ctx, hash, clientID, false) 可删除种子")
 		// _ = client.DeleteTorrent(ctx, targetHash, result.Torrents[0].ClientAlias, false)
 	})
+
+	// 5. 暂停/继续等操作演示 (代码演示)
+	t.Run("种子操作演示", func(t *testing.T) {
+		t.Log("演示：通过 client.PauseTorrent / client.ResumeTorrents 等方法可控制种子")
+		// _ = client.PauseTorrent(ctx, targetHash, clientID)
+		// res := client.ResumeTorrents(ctx, []vertex.TorrentRef{{Hash: targetHash, ClientID: clientID}})
+		// _ = res.Err()
+	})
 }
 
 // TestRequestTimeout 示例：演示如何为单个高耗时请求设置独立超时
@@ -715,3 +742,65 @@ func TestDeleteRuleCRUD(t *testing.T) {
 		})
 	})
 }
+
+// TestWatcher 示例：监听种子与下载器的状态变化
+func TestWatcher(t *testing.T) {
+	watchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	w := vertex.NewWatcher(client,
+		vertex.WithWatchInterval(time.Second),
+		vertex.WithSpeedThreshold(vertex.SpeedThreshold{Direction: vertex.SpeedUpload, BytesPerSecond: 1024}),
+	)
+
+	for ev := range w.Watch(watchCtx) {
+		switch ev.Type {
+		case vertex.EventError:
+			t.Logf("轮询出错: %v", ev.Err)
+		case vertex.EventTorrentAdded, vertex.EventTorrentRemoved, vertex.EventTorrentStateChanged:
+			t.Logf("种子事件 [%s]: %s", ev.Type, ev.Torrent.Name)
+		default:
+			t.Logf("下载器事件 [%s]: %s", ev.Type, ev.Downloader.Alias)
+		}
+	}
+	t.Log("✅ 监听已随 ctx 结束而关闭")
+}
+
+// TestExporter 示例：以 Prometheus 文本格式导出监控指标
+func TestExporter(t *testing.T) {
+	e := exporter.New(client, exporter.WithCacheTTL(10*time.Second))
+	body := e.Scrape(ctx)
+	t.Logf("共导出 %d 字节指标数据", len(body))
+	lines := strings.Split(string(body), "\n")
+	if len(lines) > 5 {
+		lines = lines[:5]
+	}
+	for _, line := range lines {
+		t.Log(line)
+	}
+}
+
+// TestPool 示例：通过客户端池并发查询多个 Vertex 实例
+func TestPool(t *testing.T) {
+	pool := vertex.NewPool(vertex.WithPoolConcurrency(2))
+	pool.Add("main", client) // 实际使用中可通过 pool.Connect 接入多台实例
+
+	res := pool.ListDownloaders(ctx)
+	if err := res.Err(); err != nil {
+		t.Fatal(err)
+	}
+	for name, list := range res.Values {
+		t.Logf("实例 [%s] 共有 %d 个下载器", name, len(list))
+	}
+}
+
+// TestQueryTorrents 示例：使用查询语句筛选种子
+func TestQueryTorrents(t *testing.T) {
+	torrents, err := client.QueryTorrents(ctx, "state=seeding and ratio < 1 order by size desc limit 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tr := range torrents {
+		t.Logf("%s | 大小: %s | 分享率: %.2f", tr.Name, formatBytes(tr.Size), tr.Ratio)
+	}
+}
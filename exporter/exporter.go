@@ -0,0 +1,347 @@
+// Package exporter 将 Vertex 的下载器与服务器监控数据以 Prometheus 文本格式导出。
+//
+// 导出器不依赖 Prometheus 客户端库，直接实现 text/plain exposition 格式，
+// 可以作为 http.Handler 挂载到任意 /metrics 路由上：
+//
+//	http.Handle("/metrics", exporter.New(client))
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iniwex5/vertex-go-sdk"
+)
+
+// contentType Prometheus 文本格式的 Content-Type
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter 是 Vertex 数据的 Prometheus 导出器
+type Exporter struct {
+	client    *vertex.Client
+	namespace string
+	ttl       time.Duration
+	timeout   time.Duration
+	vnstat    bool
+
+	mu       sync.Mutex // 刷新期间持有锁，保证并发抓取只会触发一次实际查询
+	cached   []byte
+	cachedAt time.Time
+}
+
+// Option 是用于配置 Exporter 的函数选项模式
+type Option func(*Exporter)
+
+// WithNamespace 配置指标名前缀 (默认 "vertex")
+func WithNamespace(ns string) Option {
+	return func(e *Exporter) {
+		e.namespace = ns
+	}
+}
+
+// WithCacheTTL 配置抓取结果的缓存时间 (默认 15 秒)，缓存期内的抓取直接复用上次结果。
+// 任一数据源查询失败的结果不会被缓存，下次抓取会重新查询
+func WithCacheTTL(d time.Duration) Option {
+	return func(e *Exporter) {
+		e.ttl = d
+	}
+}
+
+// WithScrapeTimeout 配置单次刷新查询 Vertex 的超时时间 (默认 10 秒)
+func WithScrapeTimeout(d time.Duration) Option {
+	return func(e *Exporter) {
+		e.timeout = d
+	}
+}
+
+// WithVnstat 配置是否逐台服务器查询 Vnstat 流量 (默认开启)
+func WithVnstat(enabled bool) Option {
+	return func(e *Exporter) {
+		e.vnstat = enabled
+	}
+}
+
+// New 创建一个导出器
+func New(c *vertex.Client, opts ...Option) *Exporter {
+	e := &Exporter{
+		client:    c,
+		namespace: "vertex",
+		ttl:       15 * time.Second,
+		timeout:   10 * time.Second,
+		vnstat:    true,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// ServeHTTP 实现 http.Handler，输出 Prometheus 文本格式
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := e.Scrape(r.Context())
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(body)
+}
+
+// Scrape 返回当前的指标文本，缓存未过期时不会访问 Vertex。
+// 有数据源查询失败 (包括 ctx 被取消) 时仍返回已获取的指标，但不缓存该结果
+func (e *Exporter) Scrape(ctx context.Context) []byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cached != nil && time.Since(e.cachedAt) < e.ttl {
+		return e.cached
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	set := newMetricSet(e.namespace)
+	if err := e.collect(ctx, set); err != nil {
+		return set.bytes()
+	}
+
+	e.cached = set.bytes()
+	e.cachedAt = time.Now()
+	return e.cached
+}
+
+// collect 查询所有数据源并写入指标，返回各数据源的错误。
+// 单个数据源失败只影响对应的 scrape_success 指标，其余指标照常输出
+func (e *Exporter) collect(ctx context.Context, set *metricSet) error {
+	start := time.Now()
+	defer func() {
+		set.gauge("scrape_duration_seconds", "刷新所有指标耗时 (秒)", nil, time.Since(start).Seconds())
+	}()
+
+	var errs []error
+	record := func(source string, err error) {
+		v := 1.0
+		if err != nil {
+			v = 0
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+		}
+		set.gauge("scrape_success", "数据源是否查询成功", labels{"source": source}, v)
+	}
+
+	downloaders, err := e.client.ListDownloaders(ctx)
+	record("downloaders", err)
+	for _, d := range downloaders {
+		l := labels{"downloader": d.Alias, "id": d.ID}
+		set.gauge("downloader_up", "下载器是否在线", l, boolValue(d.Status))
+		set.gauge("downloader_upload_speed_bytes", "下载器上传速度 (B/s)", l, d.UploadSpeed)
+		set.gauge("downloader_download_speed_bytes", "下载器下载速度 (B/s)", l, d.DownloadSpeed)
+		set.counter("downloader_uploaded_bytes_total", "下载器累计上传量", l, float64(d.AllTimeUpload))
+		set.counter("downloader_downloaded_bytes_total", "下载器累计下载量", l, float64(d.AllTimeDownload))
+		set.gauge("downloader_seeding", "做种中种子数", l, float64(d.SeedingCount))
+		set.gauge("downloader_leeching", "下载中种子数", l, float64(d.LeechingCount))
+	}
+
+	servers, err := e.client.ListServers(ctx)
+	record("servers", err)
+	serverIDs := make(map[string]bool, len(servers))
+	for _, s := range servers {
+		serverIDs[s.ID] = true
+		set.gauge("server_up", "服务器是否在线", labels{"server": s.ID, "alias": s.Alias}, boolValue(s.Status && s.Enable))
+	}
+
+	monitors := []struct {
+		source, name, help string
+		fetch              func(context.Context) (map[string]interface{}, error)
+	}{
+		{"cpu", "server_cpu_use", "服务器 CPU 使用情况", e.client.GetServerCpuUse},
+		{"memory", "server_memory", "服务器内存使用情况", e.client.GetServerMemoryUse},
+		{"disk", "server_disk", "服务器磁盘使用情况", e.client.GetServerDiskUse},
+		{"netSpeed", "server_net_speed", "服务器实时网速", e.client.GetServerNetSpeed},
+	}
+	for _, m := range monitors {
+		data, err := m.fetch(ctx)
+		record(m.source, err)
+		for _, p := range splitByServer(data, serverIDs) {
+			set.gauge(m.name, m.help, labels{"server": p.server, "field": p.field}, p.value)
+		}
+	}
+
+	if e.vnstat {
+		// 每台服务器单独查询，但只输出一条 scrape_success，避免重复样本导致整次抓取被拒绝
+		var vnstatErrs []error
+		for _, s := range servers {
+			info, err := e.client.GetServerVnstat(ctx, s.ID)
+			if err != nil {
+				vnstatErrs = append(vnstatErrs, err)
+				continue
+			}
+			// 只导出当月流量：按月份展开的字段会随时间不断产生新的时间序列
+			if p, ok := info.Series(vertex.VnstatMonth).Last(); ok {
+				set.gauge("server_traffic_current_month_bytes", "服务器 Vnstat 当月流量 (字节)", labels{"server": s.ID, "direction": "rx"}, float64(p.Rx))
+				set.gauge("server_traffic_current_month_bytes", "服务器 Vnstat 当月流量 (字节)", labels{"server": s.ID, "direction": "tx"}, float64(p.Tx))
+			}
+		}
+		if len(servers) > 0 {
+			record("vnstat", errors.Join(vnstatErrs...))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// point 一个数值叶子节点
+type point struct {
+	server string
+	field  string
+	value  float64
+}
+
+// splitByServer 将监控接口返回的数据拆成按服务器分组的数值。
+// 顶层键为已知服务器 ID 时作为 server 标签，否则视为字段名 (server 标签为空)。
+func splitByServer(data map[string]interface{}, serverIDs map[string]bool) []point {
+	var points []point
+	for _, key := range sortedKeys(data) {
+		v := data[key]
+		if serverIDs[key] {
+			for _, p := range flatten("", v) {
+				p.server = key
+				if p.field == "" {
+					p.field = "value"
+				}
+				points = append(points, p)
+			}
+			continue
+		}
+		points = append(points, flatten(key, v)...)
+	}
+	return points
+}
+
+// flatten 递归展开嵌套对象中的所有数值，字段路径以下划线连接
+func flatten(prefix string, v interface{}) []point {
+	switch val := v.(type) {
+	case float64:
+		return []point{{field: prefix, value: val}}
+	case bool:
+		return []point{{field: prefix, value: boolValue(val)}}
+	case string:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return []point{{field: prefix, value: f}}
+		}
+	case map[string]interface{}:
+		var points []point
+		for _, key := range sortedKeys(val) {
+			field := key
+			if prefix != "" {
+				field = prefix + "_" + key
+			}
+			points = append(points, flatten(field, val[key])...)
+		}
+		return points
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ==========================================
+// 文本格式输出
+// ==========================================
+
+// labels 指标标签
+type labels map[string]string
+
+// family 同名指标的集合
+type family struct {
+	help    string
+	kind    string
+	samples []string
+}
+
+// metricSet 按指标名聚合样本，保证 HELP/TYPE 只输出一次
+type metricSet struct {
+	namespace string
+	families  map[string]*family
+}
+
+func newMetricSet(namespace string) *metricSet {
+	return &metricSet{namespace: namespace, families: make(map[string]*family)}
+}
+
+func (s *metricSet) gauge(name, help string, l labels, v float64) {
+	s.add(name, help, "gauge", l, v)
+}
+
+func (s *metricSet) counter(name, help string, l labels, v float64) {
+	s.add(name, help, "counter", l, v)
+}
+
+func (s *metricSet) add(name, help, kind string, l labels, v float64) {
+	if s.namespace != "" {
+		name = s.namespace + "_" + name
+	}
+	f, ok := s.families[name]
+	if !ok {
+		f = &family{help: help, kind: kind}
+		s.families[name] = f
+	}
+	f.samples = append(f.samples, name+formatLabels(l)+" "+strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+// bytes 输出完整的文本格式内容
+func (s *metricSet) bytes() []byte {
+	names := make([]string, 0, len(s.families))
+	for name := range s.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		f := s.families[name]
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, f.kind)
+		for _, sample := range f.samples {
+			buf.WriteString(sample)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+// labelEscaper 按 exposition 格式转义标签值
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(l labels) string {
+	if len(l) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+`="`+labelEscaper.Replace(l[k])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
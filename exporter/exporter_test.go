@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iniwex5/vertex-go-sdk"
+)
+
+var update = flag.Bool("update", false, "更新 testdata 下的 golden 文件")
+
+// fakeVertex 返回固定数据的 Vertex 接口，failVnstat 中的服务器查询 Vnstat 时返回业务错误
+func fakeVertex(t *testing.T, failVnstat map[string]bool) *vertex.Client {
+	t.Helper()
+	vnstat := func(rx, tx float64) map[string]interface{} {
+		return map[string]interface{}{
+			"month": map[string]interface{}{
+				"2026-09": map[string]interface{}{"rx": rx / 2, "tx": tx / 2},
+				"2026-10": map[string]interface{}{"rx": rx, "tx": tx},
+			},
+		}
+	}
+	data := map[string]interface{}{
+		"/api/downloader/list": []map[string]interface{}{
+			{"id": "qb1", "alias": "qb-\"home\"", "status": true, "uploadSpeed": 1024, "downloadSpeed": 0, "allTimeUpload": 1e9, "allTimeDownload": 5e8, "seedingCount": 3, "leechingCount": 1},
+		},
+		"/api/server/list": []map[string]interface{}{
+			{"id": "s1", "alias": "box-1", "enable": true, "status": true},
+			{"id": "s2", "alias": "box-2", "enable": true, "status": false},
+		},
+		"/api/server/cpuUse":    map[string]interface{}{"s1": 12.5, "s2": 40},
+		"/api/server/memoryUse": map[string]interface{}{"s1": map[string]interface{}{"used": 1024, "total": 4096}, "s2": map[string]interface{}{"used": 2048, "total": 4096}},
+		"/api/server/diskUse":   map[string]interface{}{"s1": map[string]interface{}{"percent": "50"}, "s2": map[string]interface{}{"percent": "75"}},
+		"/api/server/netSpeed":  map[string]interface{}{"s1": map[string]interface{}{"up": 100, "down": 200}, "s2": map[string]interface{}{"up": 300, "down": 400}},
+	}
+	vnstats := map[string]interface{}{"s1": vnstat(3000, 1000), "s2": vnstat(6000, 2000)}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]interface{}{"success": true}
+		switch {
+		case r.URL.Path == "/api/server/vnstat":
+			id := r.URL.Query().Get("id")
+			if failVnstat[id] {
+				resp = map[string]interface{}{"success": false, "message": "vnstat 未安装"}
+			} else {
+				resp["data"] = vnstats[id]
+			}
+		case data[r.URL.Path] != nil:
+			resp["data"] = data[r.URL.Path]
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := vertex.NewClient(context.Background(), srv.URL, vertex.WithoutRetry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// stable 去掉每次抓取都会变化的耗时指标
+func stable(body []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range strings.SplitAfter(string(body), "\n") {
+		if !strings.Contains(line, "scrape_duration_seconds") {
+			out.WriteString(line)
+		}
+	}
+	return out.Bytes()
+}
+
+func TestScrapeGolden(t *testing.T) {
+	got := stable(New(fakeVertex(t, nil)).Scrape(context.Background()))
+
+	golden := filepath.Join("testdata", "metrics.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("输出与 %s 不一致 (使用 -update 更新):\n%s", golden, got)
+	}
+}
+
+func TestScrapeNoDuplicateSamples(t *testing.T) {
+	for name, fail := range map[string]map[string]bool{
+		"全部成功": nil,
+		"部分失败": {"s2": true},
+		"全部失败": {"s1": true, "s2": true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body := string(New(fakeVertex(t, fail)).Scrape(context.Background()))
+
+			seen := make(map[string]bool)
+			for _, line := range strings.Split(body, "\n") {
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+				series := line[:strings.LastIndex(line, " ")]
+				if seen[series] {
+					t.Errorf("重复样本: %s", series)
+				}
+				seen[series] = true
+			}
+
+			want := `vertex_scrape_success{source="vnstat"} 1`
+			if len(fail) > 0 {
+				want = `vertex_scrape_success{source="vnstat"} 0`
+			}
+			if !strings.Contains(body, want+"\n") {
+				t.Errorf("缺少 %s:\n%s", want, body)
+			}
+		})
+	}
+}
+
+func TestScrapeDoesNotCacheFailures(t *testing.T) {
+	e := New(fakeVertex(t, nil), WithCacheTTL(time.Hour))
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	ok := `vertex_scrape_success{source="downloaders"} 1` + "\n"
+
+	if body := string(e.Scrape(cancelled)); strings.Contains(body, ok) {
+		t.Fatalf("ctx 已取消时查询不应成功:\n%s", body)
+	}
+	// 失败的结果没有被缓存，下一次抓取重新查询
+	if body := string(e.Scrape(context.Background())); !strings.Contains(body, ok) {
+		t.Fatalf("失败的抓取结果被缓存:\n%s", body)
+	}
+	// 成功的结果在缓存期内直接复用，不再访问 Vertex
+	if body := string(e.Scrape(cancelled)); !strings.Contains(body, ok) {
+		t.Fatalf("成功的抓取结果应被缓存:\n%s", body)
+	}
+}
@@ -0,0 +1,371 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// 集群健康报告 (Health Report)
+// ==========================================
+
+// HealthLevel 健康状态级别
+type HealthLevel int
+
+const (
+	HealthOK   HealthLevel = iota // 正常
+	HealthWarn                    // 警告
+	HealthCrit                    // 严重
+)
+
+// String 返回 OK / WARN / CRIT
+func (l HealthLevel) String() string {
+	switch l {
+	case HealthWarn:
+		return "WARN"
+	case HealthCrit:
+		return "CRIT"
+	default:
+		return "OK"
+	}
+}
+
+// MarshalJSON 以字符串形式输出级别
+func (l HealthLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// emoji 用于 Markdown 输出
+func (l HealthLevel) emoji() string {
+	switch l {
+	case HealthWarn:
+		return "⚠️"
+	case HealthCrit:
+		return "❌"
+	default:
+		return "✅"
+	}
+}
+
+// HealthThresholds 健康检查阈值，百分比字段为 0 时使用默认值
+type HealthThresholds struct {
+	CPUWarn, CPUCrit       float64 // CPU 使用率 (%)，默认 80 / 95
+	MemoryWarn, MemoryCrit float64 // 内存使用率 (%)，默认 85 / 95
+	DiskWarn, DiskCrit     float64 // 磁盘使用率 (%)，默认 85 / 95
+
+	MinSeeding     int     // 下载器做种数低于该值时警告，0 表示不检查
+	MinUploadSpeed float64 // 下载器上传速度 (B/s) 低于该值时警告，0 表示不检查
+
+	// Quotas 需要检查的流量配额：已超额为 CRIT，预计超额为 WARN
+	Quotas []TrafficQuota
+}
+
+func (t HealthThresholds) withDefaults() HealthThresholds {
+	def := func(v *float64, d float64) {
+		if *v == 0 {
+			*v = d
+		}
+	}
+	def(&t.CPUWarn, 80)
+	def(&t.CPUCrit, 95)
+	def(&t.MemoryWarn, 85)
+	def(&t.MemoryCrit, 95)
+	def(&t.DiskWarn, 85)
+	def(&t.DiskCrit, 95)
+	return t
+}
+
+// HealthItem 单个检查项
+type HealthItem struct {
+	Category string      `json:"category"` // 服务器 / 下载器 / 流量 / 接口
+	ID       string      `json:"id,omitempty"`
+	Name     string      `json:"name"`
+	Check    string      `json:"check"` // 检查项，如 status、cpu、disk、seeding
+	Level    HealthLevel `json:"level"`
+	Message  string      `json:"message"`
+}
+
+// HealthReport 集群健康报告
+type HealthReport struct {
+	GeneratedAt time.Time    `json:"generatedAt"`
+	Level       HealthLevel  `json:"level"` // 所有检查项中最严重的级别
+	Items       []HealthItem `json:"items"`
+}
+
+// HealthCheck 汇总服务器、下载器、资源使用率与流量配额，生成健康报告。
+// 单个数据源查询失败不会中断检查，而是作为 "接口" 类检查项记录在报告中。
+func (c *Client) HealthCheck(ctx context.Context, th HealthThresholds) *HealthReport {
+	th = th.withDefaults()
+	r := &HealthReport{GeneratedAt: time.Now()}
+	apiFailed := func(source string, err error) {
+		r.add(HealthItem{Category: "接口", Name: source, Check: "fetch", Level: HealthCrit, Message: err.Error()})
+	}
+
+	servers, err := c.ListServers(ctx)
+	if err != nil {
+		apiFailed("服务器列表", err)
+	}
+	monitors := []struct {
+		check, name string
+		warn, crit  float64
+		fetch       func(context.Context) (map[string]interface{}, error)
+	}{
+		{"cpu", "CPU", th.CPUWarn, th.CPUCrit, c.GetServerCpuUse},
+		{"memory", "内存", th.MemoryWarn, th.MemoryCrit, c.GetServerMemoryUse},
+		{"disk", "磁盘", th.DiskWarn, th.DiskCrit, c.GetServerDiskUse},
+	}
+	usage := make([]map[string]interface{}, len(monitors))
+	for i, m := range monitors {
+		if len(servers) == 0 {
+			break
+		}
+		if usage[i], err = m.fetch(ctx); err != nil {
+			apiFailed(m.name+"使用率", err)
+		}
+	}
+	for _, s := range servers {
+		item := HealthItem{Category: "服务器", ID: s.ID, Name: s.Alias, Check: "status", Message: "在线"}
+		switch {
+		case !s.Enable:
+			item.Message = "已禁用"
+			r.add(item)
+			continue
+		case !s.Status:
+			item.Level, item.Message = HealthCrit, "离线"
+		}
+		r.add(item)
+
+		for i, m := range monitors {
+			pct, ok := utilization(usage[i][s.ID])
+			if !ok {
+				continue
+			}
+			r.add(HealthItem{
+				Category: "服务器", ID: s.ID, Name: s.Alias, Check: m.check,
+				Level:   levelOf(pct, m.warn, m.crit),
+				Message: fmt.Sprintf("%s使用率 %.1f%%", m.name, pct),
+			})
+		}
+	}
+
+	downloaders, err := c.ListDownloaders(ctx)
+	if err != nil {
+		apiFailed("下载器列表", err)
+	}
+	for _, d := range downloaders {
+		item := HealthItem{Category: "下载器", ID: d.ID, Name: d.Alias, Check: "status"}
+		switch {
+		case !d.Enable:
+			item.Message = "已禁用"
+			r.add(item)
+			continue
+		case !d.Status:
+			item.Level, item.Message = HealthCrit, "无法连接"
+			r.add(item)
+			continue
+		}
+		item.Message = fmt.Sprintf("在线，做种 %d，下载 %d，⬆️ %s/s ⬇️ %s/s",
+			d.SeedingCount, d.LeechingCount, formatBytes(int64(d.UploadSpeed)), formatBytes(int64(d.DownloadSpeed)))
+		r.add(item)
+
+		if th.MinSeeding > 0 && d.SeedingCount < th.MinSeeding {
+			r.add(HealthItem{Category: "下载器", ID: d.ID, Name: d.Alias, Check: "seeding", Level: HealthWarn,
+				Message: fmt.Sprintf("做种数 %d 低于 %d", d.SeedingCount, th.MinSeeding)})
+		}
+		if th.MinUploadSpeed > 0 && d.UploadSpeed < th.MinUploadSpeed {
+			r.add(HealthItem{Category: "下载器", ID: d.ID, Name: d.Alias, Check: "uploadSpeed", Level: HealthWarn,
+				Message: fmt.Sprintf("上传速度 %s/s 低于 %s/s", formatBytes(int64(d.UploadSpeed)), formatBytes(int64(th.MinUploadSpeed)))})
+		}
+	}
+
+	if len(th.Quotas) > 0 {
+		aliases := make(map[string]string, len(servers))
+		for _, s := range servers {
+			aliases[s.ID] = s.Alias
+		}
+		for _, st := range NewQuotaTracker(c, th.Quotas...).Check(ctx) {
+			item := HealthItem{Category: "流量", ID: st.ServerID, Name: aliases[st.ServerID], Check: "quota"}
+			if item.Name == "" {
+				item.Name = st.ServerID
+			}
+			if st.Err != nil {
+				item.Level, item.Message = HealthWarn, st.Err.Error()
+				r.add(item)
+				continue
+			}
+			item.Message = fmt.Sprintf("本周期已用 %s / %s (%.1f%%)，预计 %s",
+				formatBytes(st.Used), formatBytes(st.Cap), st.UsedRatio()*100, formatBytes(st.Projected))
+			switch {
+			case st.Exceeded():
+				item.Level = HealthCrit
+			case st.WillExceed():
+				item.Level = HealthWarn
+			}
+			r.add(item)
+		}
+	}
+	return r
+}
+
+func (r *HealthReport) add(item HealthItem) {
+	if item.Level > r.Level {
+		r.Level = item.Level
+	}
+	r.Items = append(r.Items, item)
+}
+
+// Count 返回指定级别的检查项数量
+func (r *HealthReport) Count(level HealthLevel) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// Problems 返回非 OK 的检查项，严重程度高的在前
+func (r *HealthReport) Problems() []HealthItem {
+	var out []HealthItem
+	for _, item := range r.Items {
+		if item.Level != HealthOK {
+			out = append(out, item)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Level > out[j].Level })
+	return out
+}
+
+func (r *HealthReport) summary() string {
+	return fmt.Sprintf("整体状态: %s (OK %d / WARN %d / CRIT %d)",
+		r.Level, r.Count(HealthOK), r.Count(HealthWarn), r.Count(HealthCrit))
+}
+
+// Text 以纯文本输出报告，只列出有问题的检查项
+func (r *HealthReport) Text() string {
+	var b strings.Builder
+	b.WriteString(r.summary())
+	b.WriteByte('\n')
+	for _, item := range r.Problems() {
+		fmt.Fprintf(&b, "[%s] %s %s · %s\n", item.Level, item.Category, item.Name, item.Message)
+	}
+	return b.String()
+}
+
+// Markdown 以 Markdown 输出报告，便于发送到聊天工具
+func (r *HealthReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s Vertex 健康报告 %s\n\n", r.Level.emoji(), r.GeneratedAt.Format("2006-01-02 15:04"))
+	b.WriteString(r.summary())
+	b.WriteString("\n")
+	problems := r.Problems()
+	if len(problems) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n| 状态 | 类型 | 名称 | 说明 |\n|---|---|---|---|\n")
+	for _, item := range problems {
+		fmt.Fprintf(&b, "| %s %s | %s | %s | %s |\n", item.Level.emoji(), item.Level, item.Category,
+			markdownEscape(item.Name), markdownEscape(item.Message))
+	}
+	return b.String()
+}
+
+// JSON 以 JSON 输出完整报告 (包含 OK 项)
+func (r *HealthReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// markdownEscape 转义表格单元格中的竖线，并将换行替换为空格，避免破坏表格结构
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+func levelOf(v, warn, crit float64) HealthLevel {
+	switch {
+	case v >= crit:
+		return HealthCrit
+	case v >= warn:
+		return HealthWarn
+	default:
+		return HealthOK
+	}
+}
+
+// utilization 从监控数据中提取使用率 (%)。支持数值、百分比字符串、
+// 含 used/total 或 percent 等字段的对象；多个分区 (如磁盘挂载点) 取最大值。
+func utilization(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(val), "%"), 64)
+		return f, err == nil
+	case map[string]interface{}:
+		used, okUsed := number(val["used"])
+		total, okTotal := number(val["total"])
+		if okUsed && okTotal && total > 0 {
+			return used / total * 100, true
+		}
+		for _, k := range []string{"percent", "usage", "use", "usePercent"} {
+			if pct, ok := utilization(val[k]); ok {
+				return pct, true
+			}
+		}
+		return maxUtilization(val)
+	case []interface{}:
+		m := make(map[string]interface{}, len(val))
+		for i, item := range val {
+			m[strconv.Itoa(i)] = item
+		}
+		return maxUtilization(m)
+	}
+	return 0, false
+}
+
+// capacityKeys 表示容量 (字节数) 而非使用率的字段
+var capacityKeys = map[string]bool{"used": true, "total": true, "free": true, "available": true, "size": true}
+
+func maxUtilization(m map[string]interface{}) (float64, bool) {
+	found, max := false, 0.0
+	for k, item := range m {
+		if _, ok := item.(float64); ok || capacityKeys[k] {
+			// 对象中的其他数值字段与容量字段 (即使以字符串表示) 不是使用率
+			continue
+		}
+		if pct, ok := utilization(item); ok && (!found || pct > max) {
+			found, max = true, pct
+		}
+	}
+	return max, found
+}
+
+func number(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(val, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatBytes 将字节数转换为人类可读的字符串
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
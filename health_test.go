@@ -0,0 +1,167 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// decodeJSON 将 JSON 文本解码为监控接口返回的通用结构
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestUtilization(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   float64
+		wantOK bool
+	}{
+		{"数值", `42.5`, 42.5, true},
+		{"百分比字符串", `" 73% "`, 73, true},
+		{"数字字符串", `"12"`, 12, true},
+		{"无效字符串", `"n/a"`, 0, false},
+		{"used/total", `{"used": 30, "total": 120}`, 25, true},
+		{"字符串 used/total", `{"used": "50", "total": "200"}`, 25, true},
+		{"total 为 0 时使用百分比字段", `{"used": 10, "total": 0, "percent": 8}`, 8, true},
+		{"percent 字段", `{"percent": "91.5%"}`, 91.5, true},
+		{"usePercent 字段", `{"usePercent": 66}`, 66, true},
+		// 只有 used 时它是字节数而不是使用率
+		{"只有 used", `{"used": 123456789}`, 0, false},
+		{"只有字符串 used", `{"used": "123456789"}`, 0, false},
+		{"容量字段不是使用率", `{"size": "500", "free": "20", "available": "20"}`, 0, false},
+		{"多个分区取最大值", `{"/": {"used": 10, "total": 100}, "/data": {"percent": 80}, "/boot": "35%"}`, 80, true},
+		{"分区数组取最大值", `[{"used": 90, "total": 100}, {"usage": 20}]`, 90, true},
+		{"忽略对象中的数值字段", `{"capacity": 2048, "disk": {"use": "15%"}}`, 15, true},
+		{"空对象", `{}`, 0, false},
+		{"null", `null`, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := utilization(decodeJSON(t, tt.input))
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("%s: utilization(%s) = %v, %v, 期望 %v, %v", tt.name, tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLevelOf(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want HealthLevel
+	}{
+		{0, HealthOK},
+		{79.9, HealthOK},
+		{80, HealthWarn},
+		{94.9, HealthWarn},
+		{95, HealthCrit},
+		{120, HealthCrit},
+	}
+	for _, tt := range tests {
+		if got := levelOf(tt.v, 80, 95); got != tt.want {
+			t.Errorf("levelOf(%v) = %s, 期望 %s", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	tests := map[string]string{
+		"plain":        "plain",
+		"a|b":          `a\|b`,
+		"line1\nline2": "line1 line2",
+		"win\r\nline":  "win line",
+		"mac\rline":    "mac line",
+		"|x|\n|y|":     `\|x\| \|y\|`,
+	}
+	for in, want := range tests {
+		if got := markdownEscape(in); got != want {
+			t.Errorf("markdownEscape(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestHealthReportMarkdown(t *testing.T) {
+	r := &HealthReport{}
+	r.add(HealthItem{Category: "服务器", Name: "vps|1", Check: "status", Message: "在线"})
+	r.add(HealthItem{Category: "服务器", Name: "vps|1", Check: "disk", Level: HealthWarn, Message: "磁盘使用率 90.0%"})
+	r.add(HealthItem{Category: "接口", Name: "下载器列表", Check: "fetch", Level: HealthCrit, Message: "请求失败:\nconnection | refused"})
+
+	md := r.Markdown()
+	lines := strings.Split(strings.TrimSpace(md), "\n")
+	var rows []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "| ") && !strings.HasPrefix(line, "| 状态") {
+			rows = append(rows, line)
+		}
+	}
+	if len(rows) != 2 {
+		t.Fatalf("表格应只包含 2 个问题项:\n%s", md)
+	}
+	// 严重程度高的在前，单元格中的竖线与换行均已转义
+	want := `| ❌ CRIT | 接口 | 下载器列表 | 请求失败: connection \| refused |`
+	if rows[0] != want {
+		t.Fatalf("第一行为 %q, 期望 %q", rows[0], want)
+	}
+	if !strings.Contains(rows[1], `vps\|1`) {
+		t.Fatalf("名称中的竖线应转义: %q", rows[1])
+	}
+	if !strings.Contains(md, "整体状态: CRIT (OK 1 / WARN 1 / CRIT 1)") {
+		t.Fatalf("摘要不符合预期:\n%s", md)
+	}
+
+	ok := &HealthReport{}
+	ok.add(HealthItem{Category: "服务器", Name: "vps", Check: "status"})
+	if strings.Contains(ok.Markdown(), "|---|") {
+		t.Fatal("没有问题项时不应输出表格")
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/server/list": staticRoute([]map[string]interface{}{
+			{"id": "s1", "alias": "vps", "enable": true, "status": true},
+			{"id": "s2", "alias": "old", "enable": true, "status": false},
+			{"id": "s3", "alias": "off", "enable": false},
+		}),
+		"/api/server/cpuUse":    staticRoute(map[string]interface{}{"s1": 12.5}),
+		"/api/server/memoryUse": staticRoute(map[string]interface{}{"s1": map[string]interface{}{"used": 900, "total": 1000}}),
+		// 只有字节数的 used 字段无法得出使用率，不生成检查项
+		"/api/server/diskUse": staticRoute(map[string]interface{}{"s1": map[string]interface{}{"used": "123456789"}}),
+		"/api/downloader/list": func(*http.Request) (interface{}, error) {
+			return nil, errors.New("模拟失败")
+		},
+	})
+
+	r := c.HealthCheck(context.Background(), HealthThresholds{})
+	checks := make(map[string]HealthLevel)
+	for _, item := range r.Items {
+		checks[item.Name+"/"+item.Check] = item.Level
+	}
+	want := map[string]HealthLevel{
+		"vps/status":  HealthOK,
+		"vps/cpu":     HealthOK,
+		"vps/memory":  HealthWarn,
+		"old/status":  HealthCrit,
+		"off/status":  HealthOK,
+		"下载器列表/fetch": HealthCrit,
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("检查项: %v, 期望 %v", checks, want)
+	}
+	for k, level := range want {
+		if got, ok := checks[k]; !ok || got != level {
+			t.Errorf("%s = %v, 期望 %s", k, got, level)
+		}
+	}
+	if r.Level != HealthCrit {
+		t.Fatalf("整体状态应为 CRIT，实际 %s", r.Level)
+	}
+}
@@ -0,0 +1,95 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testRoute 模拟接口的处理函数，返回的错误会转换为 success=false 的业务错误
+type testRoute func(r *http.Request) (interface{}, error)
+
+// testServer 模拟 Vertex 接口的测试服务器，记录每个接口的调用次数
+type testServer struct {
+	mu     sync.Mutex
+	routes map[string]testRoute
+	calls  map[string]int
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.calls[r.URL.Path]++
+	route, ok := s.routes[r.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := route(r)
+	resp := map[string]interface{}{"success": err == nil, "data": data}
+	if err != nil {
+		resp["message"] = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Calls 返回接口的调用次数
+func (s *testServer) Calls(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[path]
+}
+
+// newTestServer 启动测试服务器，返回服务器与其地址
+func newTestServer(t *testing.T, routes map[string]testRoute) (*testServer, string) {
+	t.Helper()
+	s := &testServer{routes: routes, calls: make(map[string]int)}
+	if s.routes == nil {
+		s.routes = make(map[string]testRoute)
+	}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, srv.URL
+}
+
+// newTestClient 启动测试服务器并创建关闭重试的客户端
+func newTestClient(t *testing.T, routes map[string]testRoute, opts ...ClientOption) (*Client, *testServer) {
+	t.Helper()
+	s, url := newTestServer(t, routes)
+	c, err := NewClient(context.Background(), url, append([]ClientOption{WithoutRetry()}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, s
+}
+
+// staticRoute 总是返回相同数据的接口
+func staticRoute(data interface{}) testRoute {
+	return func(*http.Request) (interface{}, error) {
+		return data, nil
+	}
+}
+
+// torrentListRoute 模拟 /api/torrent/list，按 clientList 过滤 (一次返回全部结果)
+func torrentListRoute(torrents func() []Torrent) testRoute {
+	return func(r *http.Request) (interface{}, error) {
+		var clients []string
+		_ = json.Unmarshal([]byte(r.URL.Query().Get("clientList")), &clients)
+		var matched []Torrent
+		for _, t := range torrents() {
+			for _, id := range clients {
+				if strings.EqualFold(t.ClientID, id) {
+					matched = append(matched, t)
+					break
+				}
+			}
+		}
+		return TorrentListResult{Torrents: matched, Total: len(matched)}, nil
+	}
+}
@@ -0,0 +1,88 @@
+package jsrule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/iniwex5/vertex-go-sdk"
+)
+
+// Fixture 录制的 Vertex 数据，用于离线测试规则代码
+type Fixture struct {
+	Torrents    []vertex.Torrent        `json:"torrents"`
+	Downloaders []vertex.DownloaderInfo `json:"downloaders"`
+}
+
+// RecordFixture 从 Vertex 读取全部种子与下载器并以 JSON 写入 w
+func RecordFixture(ctx context.Context, c *vertex.Client, w io.Writer) error {
+	downloaders, err := c.ListDownloaders(ctx)
+	if err != nil {
+		return err
+	}
+	torrents, err := c.ListAllTorrents(ctx, vertex.TorrentListOption{})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Fixture{Torrents: torrents, Downloaders: downloaders})
+}
+
+// ReadFixture 从 r 读取录制的数据
+func ReadFixture(r io.Reader) (*Fixture, error) {
+	var f Fixture
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("解析 fixture 失败: %w", err)
+	}
+	return &f, nil
+}
+
+// LoadFixture 从文件读取录制的数据
+func LoadFixture(path string) (*Fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadFixture(file)
+}
+
+// Downloader 按 ID 查找下载器
+func (f *Fixture) Downloader(id string) (vertex.DownloaderInfo, bool) {
+	for _, d := range f.Downloaders {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return vertex.DownloaderInfo{}, false
+}
+
+// MainData 根据下载器状态构造 maindata，freeSpace 为模拟的剩余磁盘空间 (Vertex 接口未提供)
+func (f *Fixture) MainData(downloaderID string, freeSpace int64) vertex.MainData {
+	d, _ := f.Downloader(downloaderID)
+	return vertex.MainData{
+		FreeSpaceOnDisk: freeSpace,
+		UploadSpeed:     int64(d.UploadSpeed),
+		DownloadSpeed:   int64(d.DownloadSpeed),
+		LeechingCount:   d.LeechingCount,
+		SeedingCount:    d.SeedingCount,
+	}
+}
+
+// Match 返回规则代码命中的种子
+func (s *Sandbox) Match(ctx context.Context, code string, main vertex.MainData, torrents []vertex.Torrent) ([]vertex.Torrent, error) {
+	var matched []vertex.Torrent
+	for i := range torrents {
+		ok, err := s.EvalDeleteRule(ctx, code, main, &torrents[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", torrents[i].Name, err)
+		}
+		if ok {
+			matched = append(matched, torrents[i])
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,20 @@
+module github.com/iniwex5/vertex-go-sdk/jsrule
+
+go 1.23.0
+
+// SDK 尚未发布版本，子模块始终使用同一仓库中的 SDK 源码
+replace github.com/iniwex5/vertex-go-sdk => ../
+
+require (
+	github.com/dop251/goja v0.0.0-20250630131328-58d95d85e994
+	github.com/iniwex5/vertex-go-sdk v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/dlclark/regexp2 v1.11.4 // indirect
+	github.com/go-resty/resty/v2 v2.17.1 // indirect
+	github.com/go-sourcemap/sourcemap v2.1.3+incompatible // indirect
+	github.com/google/pprof v0.0.0-20230207041349-798e818bf904 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
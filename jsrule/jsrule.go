@@ -0,0 +1,161 @@
+// Package jsrule 在纯 Go 的 JavaScript 引擎 (goja) 中执行 Vertex 的 JavaScript 类型规则，
+// 便于在 CI 中对 RssRule / DeleteRule 的 Code 做单元测试，而无需部署到 Vertex 后等待结果。
+//
+// 规则代码与 Vertex 中的写法一致：
+//
+//	删种规则: (maindata, torrent) => { return torrent.ratio > 2; }
+//	选种规则: (torrent) => { return torrent.size < 10 * 1024 ** 3; }
+//
+// Sandbox 实现了 vertex.RuleEvaluator，可直接用于 vertex.PlanOption.Evaluator。
+package jsrule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/iniwex5/vertex-go-sdk"
+)
+
+// Sandbox JavaScript 规则执行环境，每次求值使用独立的运行时，并发安全
+type Sandbox struct {
+	timeout time.Duration
+}
+
+// Option 是用于配置 Sandbox 的函数选项模式
+type Option func(*Sandbox)
+
+// WithTimeout 配置单次求值的超时时间 (默认 1s)，用于终止死循环等异常代码
+func WithTimeout(d time.Duration) Option {
+	return func(s *Sandbox) {
+		s.timeout = d
+	}
+}
+
+// New 创建 JavaScript 规则执行环境
+func New(opts ...Option) *Sandbox {
+	s := &Sandbox{timeout: time.Second}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ErrTimeout 规则执行超时
+var ErrTimeout = errors.New("规则执行超时")
+
+// RssItem 选种规则收到的 RSS 条目 (torrent 参数)
+type RssItem struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	Hash        string `json:"hash"`
+	ID          string `json:"id"`
+	URL         string `json:"url"`  // 种子详情页
+	Link        string `json:"link"` // 种子下载链接
+	Description string `json:"description"`
+	PubTime     int64  `json:"pubTime"` // 发布时间 (Unix 秒)
+}
+
+// EvalDeleteRule 执行删种规则代码 (maindata, torrent) => boolean
+func (s *Sandbox) EvalDeleteRule(ctx context.Context, code string, main vertex.MainData, t *vertex.Torrent) (bool, error) {
+	return s.Eval(ctx, code, main, t)
+}
+
+// EvalRssRule 执行选种规则代码 (torrent) => boolean
+func (s *Sandbox) EvalRssRule(ctx context.Context, code string, item RssItem) (bool, error) {
+	return s.Eval(ctx, code, item)
+}
+
+// Eval 执行返回函数的规则代码，args 会按 JSON 字段名转换为 JavaScript 对象后传入，
+// 函数返回值按 JavaScript 的真值规则转换为 bool
+func (s *Sandbox) Eval(ctx context.Context, code string, args ...interface{}) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	vm := goja.New()
+
+	jsArgs := make([]goja.Value, len(args))
+	for i, arg := range args {
+		v, err := toJSValue(vm, arg)
+		if err != nil {
+			return false, fmt.Errorf("转换第 %d 个参数失败: %w", i+1, err)
+		}
+		jsArgs[i] = v
+	}
+
+	// 超时或 ctx 取消时中断执行
+	done := make(chan struct{})
+	defer close(done)
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	go func() {
+		select {
+		case <-timer.C:
+			vm.Interrupt(ErrTimeout)
+		case <-ctx.Done():
+			vm.Interrupt(ctx.Err())
+		case <-done:
+		}
+	}()
+
+	result, err := run(vm, code, jsArgs)
+	if err != nil {
+		var interrupted *goja.InterruptedError
+		if errors.As(err, &interrupted) {
+			if cause, ok := interrupted.Value().(error); ok {
+				return false, cause
+			}
+		}
+		return false, err
+	}
+	return result.ToBoolean(), nil
+}
+
+func run(vm *goja.Runtime, code string, args []goja.Value) (goja.Value, error) {
+	fnValue, err := vm.RunString("(" + code + "\n)")
+	if err != nil {
+		return nil, fmt.Errorf("规则代码错误: %w", err)
+	}
+	fn, ok := goja.AssertFunction(fnValue)
+	if !ok {
+		return nil, errors.New("规则代码必须是一个函数")
+	}
+	result, err := fn(goja.Undefined(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("规则执行失败: %w", err)
+	}
+	return result, nil
+}
+
+// toJSValue 通过 JSON 将 Go 值转换为 JavaScript 值，使字段名与 Vertex 传入的对象一致
+func toJSValue(vm *goja.Runtime, v interface{}) (goja.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	switch t := v.(type) {
+	case vertex.Torrent:
+		toTorrentWire(generic, t)
+	case *vertex.Torrent:
+		if t != nil {
+			toTorrentWire(generic, *t)
+		}
+	}
+	return vm.ToValue(generic), nil
+}
+
+// toTorrentWire 将种子对象还原为 Vertex 传给规则的形式：
+// SDK 中 Tags 为 []string，而 Vertex 传入的 torrent.tags 是逗号分隔的字符串
+func toTorrentWire(generic interface{}, t vertex.Torrent) {
+	if obj, ok := generic.(map[string]interface{}); ok {
+		obj["tags"] = strings.Join(t.Tags, ",")
+	}
+}
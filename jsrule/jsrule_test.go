@@ -0,0 +1,133 @@
+package jsrule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iniwex5/vertex-go-sdk"
+)
+
+func loadFixture(t *testing.T) *Fixture {
+	t.Helper()
+	f, err := LoadFixture("testdata/fixture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestDeleteRuleMatch(t *testing.T) {
+	f := loadFixture(t)
+	s := New()
+	main := f.MainData("d1", 5<<30)
+
+	code := `(maindata, torrent) => {
+  return maindata.freeSpaceOnDisk < 10 * 1024 ** 3
+    && torrent.state !== 'downloading'
+    && !torrent.tags.includes('keep');
+}`
+	matched, err := s.Match(context.Background(), code, main, f.Torrents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matched) != 1 || matched[0].Hash != "b2" {
+		t.Fatalf("期望只命中 b2，实际: %+v", matched)
+	}
+}
+
+func TestTorrentTagsWireShape(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	code := `(maindata, torrent) => typeof torrent.tags === 'string'
+  && torrent.tags.split(',').includes('keep')`
+
+	for _, tt := range []struct {
+		tags []string
+		want bool
+	}{
+		{[]string{"hd", "keep"}, true},
+		{[]string{"keeper"}, false}, // 按标签而不是子串匹配
+		{nil, false},
+	} {
+		torrent := vertex.Torrent{Hash: "a", Tags: tt.tags}
+		got, err := s.EvalDeleteRule(ctx, code, vertex.MainData{}, &torrent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("tags=%q: 得到 %v, 期望 %v", tt.tags, got, tt.want)
+		}
+	}
+
+	// 与 Vertex 一致：多个标签以逗号连接，没有标签时为空字符串
+	ok, err := s.Eval(ctx, `(torrent) => torrent.tags === 'hd,keep'`, vertex.Torrent{Tags: []string{"hd", "keep"}})
+	if err != nil || !ok {
+		t.Fatalf("按值传入的种子 tags 应为逗号分隔的字符串: %v, %v", ok, err)
+	}
+	ok, err = s.Eval(ctx, `(torrent) => torrent.tags === ''`, &vertex.Torrent{})
+	if err != nil || !ok {
+		t.Fatalf("没有标签时 tags 应为空字符串: %v, %v", ok, err)
+	}
+}
+
+func TestRssRule(t *testing.T) {
+	s := New()
+	code := `(torrent) => torrent.name.includes('1080p') && torrent.size < 50 * 1024 ** 3`
+	ok, err := s.EvalRssRule(context.Background(), code, RssItem{Name: "Movie.1080p", Size: 10 << 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("期望命中")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := New(WithTimeout(50 * time.Millisecond))
+	ctx := context.Background()
+	torrent := &vertex.Torrent{Name: "x"}
+
+	if _, err := s.EvalDeleteRule(ctx, `(maindata, torrent) => { while (true) {} }`, vertex.MainData{}, torrent); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("期望超时错误，实际: %v", err)
+	}
+	if _, err := s.EvalDeleteRule(ctx, `(maindata, torrent) => {`, vertex.MainData{}, torrent); err == nil {
+		t.Fatal("期望语法错误")
+	}
+	if _, err := s.EvalDeleteRule(ctx, `42`, vertex.MainData{}, torrent); err == nil {
+		t.Fatal("期望非函数错误")
+	}
+	if _, err := s.EvalDeleteRule(ctx, `(maindata, torrent) => torrent.missing.field`, vertex.MainData{}, torrent); err == nil {
+		t.Fatal("期望运行时错误")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.EvalDeleteRule(cancelled, `() => true`, vertex.MainData{}, torrent); !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望取消错误，实际: %v", err)
+	}
+}
+
+func TestPlannerEvaluator(t *testing.T) {
+	f := loadFixture(t)
+	d, _ := f.Downloader("d1")
+	d.DeleteRules = []string{"js"}
+	rules := []vertex.DeleteRule{{
+		ID:   "js",
+		Type: string(vertex.RuleTypeJavaScript),
+		Code: `(maindata, torrent) => torrent.ratio < 1 && torrent.progress === 1`,
+	}}
+
+	plan, err := vertex.PlanDeletions(context.Background(), d, rules, f.Torrents, vertex.PlanOption{
+		FreeSpace:       1 << 30,
+		TargetFreeSpace: 2 << 30,
+		Evaluator:       New(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Skipped) != 0 || len(plan.Items) != 1 || !plan.Reached() {
+		t.Fatalf("规划结果不符合预期: %+v", plan)
+	}
+}
@@ -0,0 +1,101 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// ==========================================
+// 结构化日志 (Logging)
+// ==========================================
+
+// WithLogger 配置结构化日志，每次 HTTP 请求 (含每次重试) 输出一条记录。
+// 请求体中的密码、Cookie 等敏感字段会被脱敏；默认不输出任何日志。
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithLogLevel 配置成功请求的日志级别 (默认 slog.LevelDebug)，失败请求至少以 Warn 级别输出
+func WithLogLevel(level slog.Level) ClientOption {
+	return func(c *Client) error {
+		c.logLevel = level
+		return nil
+	}
+}
+
+// LoggingMiddleware 返回使用 slog 记录每次调用的中间件，与 WithLogger 不同，一次逻辑调用 (含所有重试) 只输出一条记录。
+// 成功的调用以 Info 级别输出，失败以 Error 级别输出；请求体中的密码、Cookie 等敏感字段会被脱敏。
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return HookMiddleware(Hooks{
+		After: func(ctx context.Context, call *Call) {
+			level, msg := slog.LevelInfo, "vertex 请求完成"
+			if call.Err != nil {
+				level, msg = slog.LevelError, "vertex 请求失败"
+			}
+			logCall(ctx, logger, level, msg, call)
+		},
+	})
+}
+
+// logAttempt 记录单次请求结果，attempt 从 0 开始，retrying 表示随后还会重试
+func (c *Client) logAttempt(ctx context.Context, req *Request, attempt int, resp *Response, err error, elapsed time.Duration, retrying bool) {
+	if c.logger == nil {
+		return
+	}
+
+	level := c.logLevel
+	if err != nil && level < slog.LevelWarn {
+		level = slog.LevelWarn
+	}
+	msg := "vertex 请求完成"
+	if err != nil {
+		msg = "vertex 请求失败"
+		if retrying {
+			msg = "vertex 请求失败，准备重试"
+		}
+	}
+	call := &Call{Request: req, Response: resp, Err: err, Duration: elapsed}
+	logCall(ctx, c.logger, level, msg, call, slog.Int("attempt", attempt))
+}
+
+// logCall 输出一次调用的日志记录，extra 为附加字段 (如重试次数)
+func logCall(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, call *Call, extra ...slog.Attr) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	req := call.Request
+	attrs := []slog.Attr{
+		slog.String("operation", req.Operation),
+		slog.String("method", req.Method),
+		slog.String("path", req.Path),
+		slog.Int("status", call.StatusCode()),
+		slog.Duration("duration", call.Duration),
+	}
+	attrs = append(attrs, extra...)
+	if req.CacheHit {
+		attrs = append(attrs, slog.Bool("cacheHit", true))
+	}
+	if len(req.Params) > 0 {
+		attrs = append(attrs, slog.Any("params", req.Params))
+	}
+	if req.Body != nil && logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.Any("body", RedactBody(req.Body)))
+	}
+
+	var apiErr *APIError
+	switch {
+	case errors.As(call.Err, &apiErr):
+		attrs = append(attrs, slog.String("message", apiErr.Message))
+	case call.Err != nil:
+		attrs = append(attrs, slog.String("error", call.Err.Error()))
+	case call.Response != nil && call.Response.Message != "":
+		attrs = append(attrs, slog.String("message", call.Response.Message))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,194 @@
+// Package metainfo 解析 .torrent 种子文件 (BEP 3 metainfo)，并计算 v1 InfoHash。
+//
+//	m, err := metainfo.Load(f)
+//	fmt.Println(m.Name, m.InfoHash, m.TotalSize(), m.Trackers())
+package metainfo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/iniwex5/vertex-go-sdk/bencode"
+)
+
+// File 种子中的单个文件
+type File struct {
+	Path   []string // 相对于种子根目录的路径分段
+	Length int64    // 文件大小 (字节)
+}
+
+// PathString 返回以 "/" 连接的相对路径
+func (f File) PathString() string {
+	return path.Join(f.Path...)
+}
+
+// MetaInfo 解析后的种子信息
+type MetaInfo struct {
+	InfoHash     string     // v1 InfoHash (小写十六进制)
+	Name         string     // 种子名 (单文件时为文件名，多文件时为根目录名)
+	PieceLength  int64      // 分块大小
+	PieceCount   int        // 分块数量
+	Files        []File     // 文件列表，单文件种子也会包含一个路径为 [Name] 的文件
+	SingleFile   bool       // 是否为单文件种子
+	Private      bool       // 是否为私有种子 (info.private = 1)
+	Source       string     // info.source 标记，PT 站点常用于区分来源
+	Announce     string     // 主 Tracker
+	AnnounceList [][]string // 分层 Tracker 列表 (BEP 12)
+	Comment      string     // 注释
+	CreatedBy    string     // 制作工具
+	CreationDate time.Time  // 制作时间，未提供时为零值
+}
+
+// TotalSize 返回所有文件的总大小
+func (m *MetaInfo) TotalSize() int64 {
+	var total int64
+	for _, f := range m.Files {
+		total += f.Length
+	}
+	return total
+}
+
+// Trackers 返回去重后的全部 Tracker 地址，按 announce-list 的层级顺序排列
+func (m *MetaInfo) Trackers() []string {
+	seen := make(map[string]bool)
+	var out []string
+	add := func(u string) {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			out = append(out, u)
+		}
+	}
+	for _, tier := range m.AnnounceList {
+		for _, u := range tier {
+			add(u)
+		}
+	}
+	add(m.Announce)
+	return out
+}
+
+// Load 读取并解析种子文件
+func Load(r io.Reader) (*MetaInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// InfoHash 计算种子文件的 v1 InfoHash (info 字典原始字节的 SHA-1)，不解析其余字段
+func InfoHash(data []byte) (string, error) {
+	raw, err := bencode.RawDict(data)
+	if err != nil {
+		return "", err
+	}
+	info, ok := raw["info"]
+	if !ok {
+		return "", errors.New("种子文件缺少 info 字典")
+	}
+	sum := sha1.Sum(info)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Parse 解析种子文件内容
+func Parse(data []byte) (*MetaInfo, error) {
+	hash, err := InfoHash(data)
+	if err != nil {
+		return nil, err
+	}
+	v, err := bencode.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	root := v.(map[string]interface{}) // RawDict 已确认顶层为字典
+	info, ok := root["info"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("种子文件的 info 不是字典")
+	}
+
+	m := &MetaInfo{
+		InfoHash:    hash,
+		Name:        str(info, "name"),
+		PieceLength: integer(info, "piece length"),
+		Private:     integer(info, "private") == 1,
+		Source:      str(info, "source"),
+		Announce:    str(root, "announce"),
+		Comment:     str(root, "comment"),
+		CreatedBy:   str(root, "created by"),
+	}
+	if utf8Name := str(info, "name.utf-8"); utf8Name != "" {
+		m.Name = utf8Name
+	}
+	if m.Name == "" {
+		return nil, errors.New("种子文件缺少 name 字段")
+	}
+	if ts := integer(root, "creation date"); ts > 0 {
+		m.CreationDate = time.Unix(ts, 0)
+	}
+	if pieces, ok := info["pieces"].(string); ok {
+		if len(pieces)%sha1.Size != 0 {
+			return nil, fmt.Errorf("pieces 长度 %d 不是 20 的倍数", len(pieces))
+		}
+		m.PieceCount = len(pieces) / sha1.Size
+	}
+
+	if tiers, ok := root["announce-list"].([]interface{}); ok {
+		for _, t := range tiers {
+			items, _ := t.([]interface{})
+			var tier []string
+			for _, item := range items {
+				if s, ok := item.(string); ok && s != "" {
+					tier = append(tier, s)
+				}
+			}
+			if len(tier) > 0 {
+				m.AnnounceList = append(m.AnnounceList, tier)
+			}
+		}
+	}
+
+	if files, ok := info["files"].([]interface{}); ok {
+		for i, f := range files {
+			fd, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("files[%d] 不是字典", i)
+			}
+			parts, _ := fd["path"].([]interface{})
+			if utf8Parts, ok := fd["path.utf-8"].([]interface{}); ok {
+				parts = utf8Parts
+			}
+			file := File{Length: integer(fd, "length")}
+			for _, p := range parts {
+				if s, ok := p.(string); ok {
+					file.Path = append(file.Path, s)
+				}
+			}
+			if len(file.Path) == 0 {
+				return nil, fmt.Errorf("files[%d] 缺少 path 字段", i)
+			}
+			m.Files = append(m.Files, file)
+		}
+	} else {
+		if _, ok := info["length"]; !ok {
+			return nil, errors.New("种子文件缺少 length 或 files 字段")
+		}
+		m.SingleFile = true
+		m.Files = []File{{Path: []string{m.Name}, Length: integer(info, "length")}}
+	}
+	return m, nil
+}
+
+func str(d map[string]interface{}, key string) string {
+	s, _ := d[key].(string)
+	return s
+}
+
+func integer(d map[string]interface{}, key string) int64 {
+	n, _ := d[key].(int64)
+	return n
+}
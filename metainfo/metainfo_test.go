@@ -0,0 +1,113 @@
+package metainfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testdata 中的种子由独立的 bencode 实现生成，期望的 InfoHash 为其 info 字典原始字节的 SHA-1
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		file       string
+		infoHash   string
+		name       string
+		singleFile bool
+		private    bool
+		source     string
+		totalSize  int64
+		pieces     int
+		files      []string
+		trackers   []string
+		created    time.Time
+	}{
+		{
+			file:       "single.torrent",
+			infoHash:   "a4a0ed9cad8b6d07cc7e3def78790c6ac4644e5f",
+			name:       "ubuntu-24.04-desktop-amd64.iso",
+			singleFile: true,
+			private:    true,
+			source:     "EX",
+			totalSize:  40000,
+			pieces:     2,
+			files:      []string{"ubuntu-24.04-desktop-amd64.iso"},
+			trackers:   []string{"http://tracker.example.com/announce"},
+			created:    time.Unix(1700000000, 0),
+		},
+		{
+			file:      "multi.torrent",
+			infoHash:  "61120faa18bd2c4f9620bd9af2193f9052fa36a3",
+			name:      "Album (2020) [FLAC]",
+			totalSize: 3300,
+			pieces:    1,
+			files:     []string{"Disc 1/01.flac", "Disc 1/02.flac", "cover.jpg"},
+			trackers:  []string{"http://a.example.com/announce", "http://b.example.com/announce", "udp://c.example.com:80"},
+		},
+		{
+			// info 字典的键未排序，重新编码会改变 InfoHash
+			file:       "unsorted.torrent",
+			infoHash:   "df7bfa322ee7a19f9ab5b20c0b5d502a0fc16215",
+			name:       "raw.bin",
+			singleFile: true,
+			totalSize:  5,
+			pieces:     1,
+			files:      []string{"raw.bin"},
+			trackers:   []string{"http://t.example.com/a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := InfoHash(data)
+			if err != nil || hash != tt.infoHash {
+				t.Fatalf("InfoHash = %s, %v, 期望 %s", hash, err, tt.infoHash)
+			}
+
+			m, err := Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for _, f := range m.Files {
+				files = append(files, f.PathString())
+			}
+			if m.InfoHash != tt.infoHash || m.Name != tt.name || m.SingleFile != tt.singleFile || m.Private != tt.private || m.Source != tt.source {
+				t.Errorf("解析结果 = %+v", m)
+			}
+			if m.TotalSize() != tt.totalSize || m.PieceCount != tt.pieces {
+				t.Errorf("大小 = %d 分块 = %d, 期望 %d %d", m.TotalSize(), m.PieceCount, tt.totalSize, tt.pieces)
+			}
+			if !reflect.DeepEqual(files, tt.files) || !reflect.DeepEqual(m.Trackers(), tt.trackers) {
+				t.Errorf("文件 = %v Tracker = %v", files, m.Trackers())
+			}
+			if !m.CreationDate.Equal(tt.created) {
+				t.Errorf("制作时间 = %v, 期望 %v", m.CreationDate, tt.created)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"顶层不是字典", "l4:infoe"},
+		{"缺少 info", "d8:announce1:ae"},
+		{"info 不是字典", "d4:info3:abce"},
+		{"缺少 name", "d4:infod6:lengthi1eee"},
+		{"缺少 length 与 files", "d4:infod4:name1:aee"},
+		{"pieces 长度错误", "d4:infod6:lengthi1e4:name1:a6:pieces3:abcee"},
+		{"非规范整数", "d4:infod6:lengthi01e4:name1:aee"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt.data)); err == nil {
+			t.Errorf("%s: 期望返回错误", tt.name)
+		}
+	}
+}
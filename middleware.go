@@ -0,0 +1,275 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// 请求中间件 (Middleware)
+// ==========================================
+
+// Request 描述一次 SDK 发往 Vertex 的 API 调用，与底层 HTTP 库无关
+type Request struct {
+	Operation string            // 对应的 Client 方法名，如 "ListTorrents"
+	Method    string            // HTTP 方法
+	Path      string            // 接口路径，如 "/api/torrent/list"
+	Params    map[string]string // 查询参数
+	Body      interface{}       // 请求体 (POST)
+	Header    http.Header       // 额外的请求头，中间件可在此注入追踪头等信息
+	CacheHit  bool              // 结果来自响应缓存或并发的相同请求，本次调用不会发起 HTTP 请求
+}
+
+// Multipart 以 multipart/form-data 方式提交的请求体，可作为 Request.Body 使用
+type Multipart struct {
+	Fields map[string]string `json:"fields"` // 普通表单字段
+	Files  []UploadFile      `json:"files"`  // 上传的文件
+}
+
+// UploadFile 上传的单个文件，内容保存在内存中以便重试时重新发送
+type UploadFile struct {
+	Field string `json:"field"` // 表单字段名
+	Name  string `json:"name"`  // 文件名
+	Data  []byte `json:"-"`     // 文件内容 (不写入日志)
+}
+
+// Handler 执行一次 API 调用
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware 包装 Handler，可在调用前后插入逻辑。
+// 中间件包裹的是一次完整的逻辑调用 (含所有重试)。
+type Middleware func(next Handler) Handler
+
+// endpointOperations 接口路径与 Client 方法名的对应关系，新增接口时需同步登记
+var endpointOperations = map[string]string{
+	"/api/user/get":              "CheckSession",
+	"/api/user/login":            "Login",
+	"/api/server/list":           "ListServers",
+	"/api/server/add":            "AddServer",
+	"/api/server/modify":         "ModifyServer",
+	"/api/server/delete":         "DeleteServer",
+	"/api/server/netSpeed":       "GetServerNetSpeed",
+	"/api/server/cpuUse":         "GetServerCpuUse",
+	"/api/server/memoryUse":      "GetServerMemoryUse",
+	"/api/server/diskUse":        "GetServerDiskUse",
+	"/api/server/vnstat":         "GetServerVnstat",
+	"/api/downloader/list":       "ListDownloaders",
+	"/api/downloader/add":        "AddDownloader",
+	"/api/downloader/modify":     "ModifyDownloader",
+	"/api/downloader/delete":     "DeleteDownloader",
+	"/api/rss/list":              "ListRss",
+	"/api/rss/add":               "AddRss",
+	"/api/rss/modify":            "ModifyRss",
+	"/api/rss/delete":            "DeleteRss",
+	"/api/rss/dryrun":            "DryRunRss",
+	"/api/rssRule/list":          "ListRssRules",
+	"/api/rssRule/add":           "AddRssRules",
+	"/api/rssRule/modify":        "ModifyRssRules",
+	"/api/rssRule/delete":        "DeleteRssRules",
+	"/api/deleteRule/list":       "ListDeleteRules",
+	"/api/deleteRule/add":        "AddDeleteRule",
+	"/api/deleteRule/modify":     "ModifyDeleteRule",
+	"/api/deleteRule/delete":     "DeleteDeleteRuleByID",
+	"/api/torrent/listHistory":   "ListRssHistory",
+	"/api/torrent/list":          "ListTorrents",
+	"/api/torrent/info":          "GetTorrentInfo",
+	"/api/torrent/link":          "LinkTorrent",
+	"/api/torrent/deleteTorrent": "DeleteTorrent",
+	"/api/torrent/addTorrent":    "AddTorrent",
+	"/api/torrent/files":         "GetTorrentFiles",
+	"/api/torrent/trackers":      "GetTorrentTrackers",
+	"/api/torrent/peers":         "GetTorrentPeers",
+	"/api/torrent/pause":         "PauseTorrent",
+	"/api/torrent/resume":        "ResumeTorrent",
+	"/api/torrent/recheck":       "RecheckTorrent",
+	"/api/torrent/reannounce":    "ReannounceTorrent",
+	"/api/torrent/setCategory":   "SetTorrentCategory",
+	"/api/torrent/setTags":       "SetTorrentTags",
+	"/api/torrent/setLimit":      "SetTorrentLimits",
+	"/api/torrent/setLocation":   "SetTorrentLocation",
+}
+
+// operationName 返回接口对应的 Client 方法名，未登记的接口返回 "METHOD /path"
+func operationName(method, path string) string {
+	if op, ok := endpointOperations[path]; ok {
+		return op
+	}
+	return method + " " + path
+}
+
+// WithMiddleware 追加请求中间件，先添加的位于外层
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) error {
+		c.middlewares = append(c.middlewares, mw...)
+		return nil
+	}
+}
+
+// Call 一次已完成调用的完整信息
+type Call struct {
+	Request  *Request
+	Response *Response // 失败时为 nil
+	Err      error
+	Duration time.Duration
+}
+
+// StatusCode 返回本次调用的 HTTP 状态码，网络错误等无法获得状态码时返回 0
+func (c *Call) StatusCode() int {
+	if c.Response != nil {
+		return c.Response.StatusCode
+	}
+	var httpErr *HTTPError
+	if errors.As(c.Err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+// Hooks 调用前后的钩子函数，均可为空
+type Hooks struct {
+	// Before 在调用前执行，可返回派生的 ctx (如注入追踪信息)
+	Before func(ctx context.Context, req *Request) context.Context
+	// After 在调用结束后执行
+	After func(ctx context.Context, call *Call)
+}
+
+// HookMiddleware 将前后钩子转换为中间件
+func HookMiddleware(h Hooks) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if h.Before != nil {
+				ctx = h.Before(ctx, req)
+			}
+			start := time.Now()
+			resp, err := next(ctx, req)
+			if h.After != nil {
+				h.After(ctx, &Call{Request: req, Response: resp, Err: err, Duration: time.Since(start)})
+			}
+			return resp, err
+		}
+	}
+}
+
+// EndpointStat 单个接口的调用统计
+type EndpointStat struct {
+	Count         int64         // 调用次数 (含命中缓存的调用)
+	CacheHits     int64         // 命中缓存的调用次数
+	Errors        int64         // 失败次数
+	TotalDuration time.Duration // 累计耗时
+	MaxDuration   time.Duration // 最长耗时
+}
+
+// AvgDuration 平均耗时
+func (s EndpointStat) AvgDuration() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Count)
+}
+
+// EndpointMetrics 按接口路径聚合调用次数、错误数与耗时，并发安全
+type EndpointMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*EndpointStat
+}
+
+// NewEndpointMetrics 创建接口统计器
+func NewEndpointMetrics() *EndpointMetrics {
+	return &EndpointMetrics{stats: make(map[string]*EndpointStat)}
+}
+
+// Middleware 返回记录统计数据的中间件
+func (m *EndpointMetrics) Middleware() Middleware {
+	return HookMiddleware(Hooks{
+		After: func(ctx context.Context, call *Call) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			s, ok := m.stats[call.Request.Path]
+			if !ok {
+				s = &EndpointStat{}
+				m.stats[call.Request.Path] = s
+			}
+			s.Count++
+			if call.Request.CacheHit {
+				s.CacheHits++
+			}
+			if call.Err != nil {
+				s.Errors++
+			}
+			s.TotalDuration += call.Duration
+			if call.Duration > s.MaxDuration {
+				s.MaxDuration = call.Duration
+			}
+		},
+	})
+}
+
+// Snapshot 返回当前统计数据的副本
+func (m *EndpointMetrics) Snapshot() map[string]EndpointStat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]EndpointStat, len(m.stats))
+	for path, s := range m.stats {
+		out[path] = *s
+	}
+	return out
+}
+
+// ==========================================
+// 敏感信息脱敏
+// ==========================================
+
+// redactedValue 脱敏后的占位内容
+const redactedValue = "******"
+
+// sensitiveKeys 需要脱敏的字段名 (小写)
+var sensitiveKeys = []string{"password", "cookie", "token", "secret", "passkey"}
+
+// isSensitiveKey 判断字段名是否包含敏感关键词
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactBody 返回请求体的脱敏副本 (JSON 通用结构)，
+// 可用于记录 DownloaderConfig、Server、登录参数等含密码的请求体
+func RedactBody(body interface{}) interface{} {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return redactedValue
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return redactedValue
+	}
+	return redactTree(v)
+}
+
+// redactTree 递归替换敏感字段的值
+func redactTree(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if isSensitiveKey(k) {
+				val[k] = redactedValue
+				continue
+			}
+			val[k] = redactTree(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactTree(item)
+		}
+	}
+	return v
+}
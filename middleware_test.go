@@ -0,0 +1,145 @@
+package vertex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestOperationName(t *testing.T) {
+	var mu sync.Mutex
+	var ops []string
+	record := HookMiddleware(Hooks{
+		Before: func(ctx context.Context, req *Request) context.Context {
+			mu.Lock()
+			ops = append(ops, req.Operation)
+			mu.Unlock()
+			return ctx
+		},
+	})
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/server/list":     staticRoute([]Server{}),
+		"/api/user/login":      staticRoute(nil),
+		"/api/downloader/list": staticRoute([]DownloaderInfo{}),
+		"/api/torrent/list":    torrentListRoute(func() []Torrent { return nil }),
+	}, WithMiddleware(record))
+	ctx := context.Background()
+
+	tests := []struct {
+		call func() error
+		want string
+	}{
+		{func() error { _, err := c.ListServers(ctx); return err }, "ListServers"},
+		{func() error { return c.Login(ctx, "admin", "pass") }, "Login"},
+		{func() error {
+			_, err := c.ListAllTorrents(ctx, TorrentListOption{ClientList: []string{"qb"}})
+			return err
+		}, "ListTorrents"},
+		{func() error { _, err := c.get(ctx, "/api/unknown", nil); return err }, "GET /api/unknown"},
+	}
+	for _, tt := range tests {
+		ops = nil
+		_ = tt.call()
+		if len(ops) == 0 || ops[0] != tt.want {
+			t.Errorf("Operation = %v, 期望 %s", ops, tt.want)
+		}
+	}
+}
+
+// TestEndpointOperationsComplete 所有接口路径都应登记操作名，且对应的 Client 方法存在
+func TestEndpointOperationsComplete(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathRe := regexp.MustCompile(`"(/api/[A-Za-z]+/[A-Za-z]+)"`)
+	clientType := reflect.TypeOf(&Client{})
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range pathRe.FindAllStringSubmatch(string(src), -1) {
+			op, ok := endpointOperations[m[1]]
+			if !ok {
+				t.Errorf("%s: 接口 %s 未登记到 endpointOperations", file, m[1])
+				continue
+			}
+			if _, ok := clientType.MethodByName(op); !ok {
+				t.Errorf("接口 %s 对应的方法 %s 不存在", m[1], op)
+			}
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := map[string]interface{}{
+		"alias":    "qb",
+		"password": "hunter2",
+		"nested":   []interface{}{map[string]interface{}{"cookie": "sid=1", "id": 1}},
+	}
+	got := RedactBody(body).(map[string]interface{})
+	if got["password"] != redactedValue || got["alias"] != "qb" {
+		t.Errorf("RedactBody = %v", got)
+	}
+	nested := got["nested"].([]interface{})[0].(map[string]interface{})
+	if nested["cookie"] != redactedValue || nested["id"] != float64(1) {
+		t.Errorf("嵌套字段 = %v", nested)
+	}
+	if body["password"] != "hunter2" {
+		t.Error("RedactBody 修改了原始请求体")
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/downloader/add": staticRoute(nil),
+		"/api/server/delete": func(*http.Request) (interface{}, error) {
+			return nil, errors.New("服务器不存在")
+		},
+	}, WithMiddleware(LoggingMiddleware(logger)))
+	ctx := context.Background()
+
+	if err := c.AddDownloader(ctx, DownloaderConfig{Alias: "qb", Password: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteServer(ctx, "s1"); err == nil {
+		t.Fatal("期望删除失败")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("每次调用应只输出一条记录:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Fatal("日志中不应出现密码原文")
+	}
+	var ok, failed map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+	if ok["level"] != "INFO" || ok["path"] != "/api/downloader/add" || ok["body"].(map[string]interface{})["password"] != redactedValue {
+		t.Errorf("成功记录不符合预期: %v", ok)
+	}
+	if failed["level"] != "ERROR" || failed["message"] != "服务器不存在" {
+		t.Errorf("失败记录不符合预期: %v", failed)
+	}
+}
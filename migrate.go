@@ -0,0 +1,400 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// 实例迁移 (Migrate)
+// ==========================================
+
+// CollisionPolicy 目标实例中已存在同别名对象时的处理方式
+type CollisionPolicy int
+
+const (
+	// CollisionFail 存在冲突时不做任何修改，直接返回错误 (默认)
+	CollisionFail CollisionPolicy = iota
+	// CollisionReuse 复用目标实例中的同名对象，引用会指向该对象
+	CollisionReuse
+	// CollisionRename 以追加后缀的别名创建新对象
+	CollisionRename
+)
+
+// MigrateOption 迁移选项。所有选择字段均为空时迁移全部下载器、RSS 任务与规则；
+// 否则只迁移选中的对象及其依赖 (如 RSS 任务使用的下载器与选种规则)。
+type MigrateOption struct {
+	Downloaders []string // 源实例中的下载器 ID
+	Rss         []string // 源实例中的 RSS 任务 ID
+	RssRules    []string // 源实例中的选种规则 ID
+	DeleteRules []string // 源实例中的删种规则 ID
+
+	OnCollision  CollisionPolicy // 别名冲突处理方式
+	RenameSuffix string          // CollisionRename 时追加的后缀，默认 " (迁移)"
+
+	// SkipRedactedSecrets 跳过源实例中密码为脱敏内容的下载器 (记录警告)，默认返回 ErrRedactedSecret
+	SkipRedactedSecrets bool
+
+	// DryRun 只生成迁移计划，不修改目标实例
+	DryRun bool
+	// ProgressFile 进度文件路径。每迁移一个对象都会写入一次，
+	// 中途失败后使用相同路径重新执行即可跳过已完成的对象；DryRun 时只读取不写入
+	ProgressFile string
+}
+
+// MigrateAction 单个对象的迁移动作
+type MigrateAction string
+
+const (
+	MigrateCreate   MigrateAction = "create"   // 新建
+	MigrateReuse    MigrateAction = "reuse"    // 复用目标实例中的同名对象
+	MigrateRename   MigrateAction = "rename"   // 改名后新建
+	MigrateConflict MigrateAction = "conflict" // 别名冲突
+	MigrateDone     MigrateAction = "done"     // 上次执行已完成
+)
+
+// MigrateStep 迁移计划中的一项
+type MigrateStep struct {
+	Kind        string        `json:"kind"`        // 对象类型
+	SourceID    string        `json:"sourceId"`    // 源实例中的 ID
+	Alias       string        `json:"alias"`       // 源实例中的别名
+	TargetAlias string        `json:"targetAlias"` // 目标实例中的别名
+	Action      MigrateAction `json:"action"`
+}
+
+// MigratePlan 迁移计划，按执行顺序排列
+type MigratePlan []MigrateStep
+
+// Conflicts 返回存在别名冲突的项
+func (p MigratePlan) Conflicts() []MigrateStep {
+	var out []MigrateStep
+	for _, s := range p {
+		if s.Action == MigrateConflict {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// String 以每行一项的形式输出计划，便于 dry-run 时打印
+func (p MigratePlan) String() string {
+	var b strings.Builder
+	for _, s := range p {
+		fmt.Fprintf(&b, "%-8s %s %s (%s)", s.Action, s.Kind, s.Alias, s.SourceID)
+		if s.TargetAlias != s.Alias {
+			fmt.Fprintf(&b, " -> %s", s.TargetAlias)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// MigrateResult 迁移结果
+type MigrateResult struct {
+	RestoreResult
+	Plan MigratePlan
+}
+
+// MigrateProgress 迁移进度，保存在 MigrateOption.ProgressFile 中
+type MigrateProgress struct {
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	IDs    IDMap    `json:"ids"`
+	Reused []string `json:"reused"` // 复用的目标对象 ID
+	// Pending 已发出创建请求、但尚未确认新 ID 的对象。
+	// 重新执行时，若目标实例中出现了该别名的新对象，则视为已完成
+	Pending   *MigratePending `json:"pending,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// MigratePending 进行中的创建操作
+type MigratePending struct {
+	Kind     string   `json:"kind"`
+	SourceID string   `json:"sourceId"`
+	Alias    string   `json:"alias"`    // 目标实例中的别名
+	Existing []string `json:"existing"` // 创建前目标实例中已有的对象 ID
+}
+
+// adopt 在目标对象列表中查找由该次创建产生的对象，返回其 ID
+func (p *MigratePending) adopt(kind, sourceID string, objs []restoreObject) string {
+	if p == nil || p.Kind != kind || p.SourceID != sourceID {
+		return ""
+	}
+	existing := make(map[string]bool, len(p.Existing))
+	for _, id := range p.Existing {
+		existing[id] = true
+	}
+	for _, o := range objs {
+		if !existing[o.ID] && o.Alias == p.Alias {
+			return o.ID
+		}
+	}
+	return ""
+}
+
+// ErrMigrateConflict 目标实例存在别名冲突
+var ErrMigrateConflict = errors.New("目标实例存在同别名对象")
+
+// Migrate 将源实例中的下载器、RSS 任务与规则复制到目标实例，并重新映射对象间的引用。
+// 冲突检查在任何修改之前完成；CollisionFail 下存在冲突时返回 ErrMigrateConflict 与完整计划。
+func Migrate(ctx context.Context, src, dst *Client, opt MigrateOption) (*MigrateResult, error) {
+	full, err := src.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("读取源实例配置失败: %w", err)
+	}
+	a := selectMigration(full, opt)
+
+	// DryRun 时同样读取进度文件，使计划反映续传后的实际操作
+	progress := &MigrateProgress{Source: src.BaseURL, Target: dst.BaseURL, IDs: NewIDMap()}
+	if opt.ProgressFile != "" {
+		if progress, err = loadMigrateProgress(opt.ProgressFile, src.BaseURL, dst.BaseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &MigrateResult{RestoreResult: RestoreResult{IDs: progress.IDs}}
+	rs := &restorer{
+		c:      dst,
+		reuse:  opt.OnCollision == CollisionReuse,
+		res:    &res.RestoreResult,
+		reused: make(map[string]bool),
+	}
+	for _, id := range progress.Reused {
+		rs.reused[id] = true
+	}
+	if a, err = rs.checkSecrets(a, opt.SkipRedactedSecrets); err != nil {
+		return res, err
+	}
+
+	if res.Plan, err = planMigration(ctx, rs, a, opt, progress.Pending); err != nil {
+		return res, err
+	}
+	if opt.OnCollision == CollisionFail && len(res.Plan.Conflicts()) > 0 {
+		return res, fmt.Errorf("%w: %d 个", ErrMigrateConflict, len(res.Plan.Conflicts()))
+	}
+	if opt.DryRun {
+		return res, nil
+	}
+
+	if opt.ProgressFile != "" {
+		// 创建前先记录，避免创建成功但读取新 ID 失败时，重新执行把该对象当作别名冲突
+		rs.beforeCreate = func(kind, oldID, alias string, existing []restoreObject) error {
+			p := &MigratePending{Kind: kind, SourceID: oldID, Alias: alias}
+			for _, o := range existing {
+				p.Existing = append(p.Existing, o.ID)
+			}
+			progress.Pending = p
+			progress.UpdatedAt = time.Now()
+			return saveMigrateProgress(opt.ProgressFile, progress)
+		}
+		rs.afterCreate = func(kind, oldID, newID string) error {
+			progress.Pending = nil
+			progress.Reused = progress.Reused[:0]
+			for id := range rs.reused {
+				progress.Reused = append(progress.Reused, id)
+			}
+			progress.UpdatedAt = time.Now()
+			return saveMigrateProgress(opt.ProgressFile, progress)
+		}
+	}
+	return res, rs.restore(ctx, a)
+}
+
+// selectMigration 按选项筛选需要迁移的对象，并补全其依赖
+func selectMigration(full *BackupArchive, opt MigrateOption) *BackupArchive {
+	a := &BackupArchive{Version: full.Version, CreatedAt: full.CreatedAt, Source: full.Source}
+	if len(opt.Downloaders)+len(opt.Rss)+len(opt.RssRules)+len(opt.DeleteRules) == 0 {
+		a.Downloaders, a.Rss, a.RssRules, a.DeleteRules = full.Downloaders, full.Rss, full.RssRules, full.DeleteRules
+		return a
+	}
+
+	set := func(ids []string) map[string]bool {
+		m := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			m[id] = true
+		}
+		return m
+	}
+	downloaders, rss, rssRules, deleteRules := set(opt.Downloaders), set(opt.Rss), set(opt.RssRules), set(opt.DeleteRules)
+
+	for _, r := range full.Rss {
+		if !rss[r.ID] {
+			continue
+		}
+		if r.Client != "" {
+			downloaders[r.Client] = true
+		}
+		for _, id := range r.SameServerClients {
+			downloaders[id] = true
+		}
+		for _, id := range append(append([]string{}, r.AcceptRules...), r.RejectRules...) {
+			rssRules[id] = true
+		}
+	}
+	// 同服务器下载器可能形成链，重复展开直到不再变化
+	for changed := true; changed; {
+		changed = false
+		for _, d := range full.Downloaders {
+			if !downloaders[d.ID] {
+				continue
+			}
+			for _, id := range d.SameServerClients {
+				if !downloaders[id] {
+					downloaders[id] = true
+					changed = true
+				}
+			}
+		}
+	}
+	for _, d := range full.Downloaders {
+		if !downloaders[d.ID] {
+			continue
+		}
+		for _, id := range append(append([]string{}, d.DeleteRules...), d.RejectDeleteRules...) {
+			deleteRules[id] = true
+		}
+	}
+
+	for _, d := range full.Downloaders {
+		if downloaders[d.ID] {
+			a.Downloaders = append(a.Downloaders, d)
+		}
+	}
+	for _, r := range full.Rss {
+		if rss[r.ID] {
+			a.Rss = append(a.Rss, r)
+		}
+	}
+	for _, r := range full.RssRules {
+		if rssRules[r.ID] {
+			a.RssRules = append(a.RssRules, r)
+		}
+	}
+	for _, r := range full.DeleteRules {
+		if deleteRules[r.ID] {
+			a.DeleteRules = append(a.DeleteRules, r)
+		}
+	}
+	return a
+}
+
+// planMigration 对比目标实例生成迁移计划。CollisionRename 时会直接修改 a 中对象的别名。
+// pending 为上次执行中未确认的创建，目标实例中已存在对应的新对象时记为已完成。
+func planMigration(ctx context.Context, rs *restorer, a *BackupArchive, opt MigrateOption, pending *MigratePending) (MigratePlan, error) {
+	_, downloaders, rss, rssRules, deleteRules := rs.kinds()
+	suffix := opt.RenameSuffix
+	if suffix == "" {
+		suffix = " (迁移)"
+	}
+
+	var (
+		plan MigratePlan
+		objs []restoreObject // 当前对象类型在目标实例中的列表
+	)
+	step := func(kind restoreKind, taken map[string]bool, id string, alias *string) {
+		s := MigrateStep{Kind: kind.name, SourceID: id, Alias: *alias, TargetAlias: *alias, Action: MigrateCreate}
+		if newID := pending.adopt(kind.name, id, objs); newID != "" && kind.ids[id] == "" {
+			kind.ids[id] = newID
+			s.TargetAlias = pending.Alias
+		}
+		switch {
+		case kind.ids[id] != "":
+			s.Action = MigrateDone
+		case !taken[*alias]:
+		case opt.OnCollision == CollisionReuse:
+			s.Action = MigrateReuse
+		case opt.OnCollision == CollisionRename:
+			s.Action = MigrateRename
+			s.TargetAlias = *alias + suffix
+			for n := 2; taken[s.TargetAlias]; n++ {
+				s.TargetAlias = fmt.Sprintf("%s%s %d", *alias, suffix, n)
+			}
+			*alias = s.TargetAlias
+		default:
+			s.Action = MigrateConflict
+		}
+		taken[s.TargetAlias] = true
+		plan = append(plan, s)
+	}
+	aliases := func(kind restoreKind) (map[string]bool, error) {
+		var err error
+		if objs, err = kind.list(ctx); err != nil {
+			return nil, fmt.Errorf("读取目标实例%s列表失败: %w", kind.name, err)
+		}
+		m := make(map[string]bool, len(objs))
+		for _, o := range objs {
+			m[o.Alias] = true
+		}
+		return m, nil
+	}
+
+	// 顺序与 restorer.restore 的创建顺序一致
+	taken, err := aliases(deleteRules)
+	if err != nil {
+		return nil, err
+	}
+	for i := range a.DeleteRules {
+		step(deleteRules, taken, a.DeleteRules[i].ID, &a.DeleteRules[i].Alias)
+	}
+	if taken, err = aliases(rssRules); err != nil {
+		return nil, err
+	}
+	for i := range a.RssRules {
+		step(rssRules, taken, a.RssRules[i].ID, &a.RssRules[i].Alias)
+	}
+	if taken, err = aliases(downloaders); err != nil {
+		return nil, err
+	}
+	for i := range a.Downloaders {
+		step(downloaders, taken, a.Downloaders[i].ID, &a.Downloaders[i].Alias)
+	}
+	if taken, err = aliases(rss); err != nil {
+		return nil, err
+	}
+	for i := range a.Rss {
+		step(rss, taken, a.Rss[i].ID, &a.Rss[i].Alias)
+	}
+	return plan, nil
+}
+
+// loadMigrateProgress 读取进度文件，文件不存在时返回空进度
+func loadMigrateProgress(path, source, target string) (*MigrateProgress, error) {
+	p := &MigrateProgress{Source: source, Target: target, IDs: NewIDMap()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("解析进度文件失败: %w", err)
+	}
+	if p.Source != source || p.Target != target {
+		return nil, fmt.Errorf("进度文件属于 %s -> %s 的迁移，与当前实例不符", p.Source, p.Target)
+	}
+	for _, m := range []*map[string]string{&p.IDs.Servers, &p.IDs.Downloaders, &p.IDs.Rss, &p.IDs.RssRules, &p.IDs.DeleteRules} {
+		if *m == nil {
+			*m = make(map[string]string)
+		}
+	}
+	return p, nil
+}
+
+// saveMigrateProgress 先写临时文件再重命名，避免中断时留下不完整的进度文件
+func saveMigrateProgress(path string, p *MigrateProgress) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("写入进度文件失败: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
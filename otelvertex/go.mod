@@ -0,0 +1,25 @@
+module github.com/iniwex5/vertex-go-sdk/otelvertex
+
+go 1.23.0
+
+// SDK 尚未发布版本，子模块始终使用同一仓库中的 SDK 源码
+replace github.com/iniwex5/vertex-go-sdk => ../
+
+require (
+	github.com/iniwex5/vertex-go-sdk v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.35.0
+	go.opentelemetry.io/otel/metric v1.35.0
+	go.opentelemetry.io/otel/sdk v1.35.0
+	go.opentelemetry.io/otel/sdk/metric v1.35.0
+	go.opentelemetry.io/otel/trace v1.35.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/go-resty/resty/v2 v2.17.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+)
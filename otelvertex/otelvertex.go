@@ -0,0 +1,177 @@
+// Package otelvertex 为 Vertex SDK 提供 OpenTelemetry 追踪与指标埋点。
+//
+// 每次 Client 方法调用 (如 ListTorrents、AddRss) 都会生成一个 span，
+// 并记录请求耗时直方图：
+//
+//	client, err := vertex.NewClient(ctx, host,
+//		vertex.WithMiddleware(otelvertex.Middleware()),
+//	)
+package otelvertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/iniwex5/vertex-go-sdk"
+)
+
+// instrumentationName 埋点库名称
+const instrumentationName = "github.com/iniwex5/vertex-go-sdk/otelvertex"
+
+// 属性键
+const (
+	AttrOperation   = attribute.Key("vertex.operation")    // Client 方法名
+	AttrEndpoint    = attribute.Key("vertex.endpoint")     // 接口路径
+	AttrSuccess     = attribute.Key("vertex.success")      // Vertex 返回的 success 标记
+	AttrMessage     = attribute.Key("vertex.message")      // Vertex 返回的业务错误信息
+	AttrResultCount = attribute.Key("vertex.result.count") // 返回的结果条数
+	AttrResultTotal = attribute.Key("vertex.result.total") // 分页查询的总条数
+	AttrMethod      = attribute.Key("http.request.method")
+	AttrStatusCode  = attribute.Key("http.response.status_code")
+)
+
+// config 埋点配置
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option 是用于配置埋点的函数选项模式
+type Option func(*config)
+
+// WithTracerProvider 指定 TracerProvider (默认使用全局 otel.GetTracerProvider())
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider 指定 MeterProvider (默认使用全局 otel.GetMeterProvider())
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// WithPropagator 指定用于向请求头注入追踪上下文的传播器 (默认使用全局 otel.GetTextMapPropagator())
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = p
+	}
+}
+
+// Middleware 返回 OpenTelemetry 埋点中间件
+func Middleware(opts ...Option) vertex.Middleware {
+	cfg := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	duration, err := meter.Float64Histogram("vertex.client.request.duration",
+		metric.WithDescription("Vertex API 调用耗时 (含重试)"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next vertex.Handler) vertex.Handler {
+		return func(ctx context.Context, req *vertex.Request) (*vertex.Response, error) {
+			common := []attribute.KeyValue{
+				AttrOperation.String(req.Operation),
+				AttrEndpoint.String(req.Path),
+				AttrMethod.String(req.Method),
+			}
+			ctx, span := tracer.Start(ctx, "vertex."+req.Operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(common...),
+			)
+			defer span.End()
+
+			if req.Header != nil {
+				cfg.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+			}
+
+			start := time.Now()
+			resp, err := next(ctx, req)
+			elapsed := time.Since(start)
+
+			status := statusCode(resp, err)
+			if status > 0 {
+				span.SetAttributes(AttrStatusCode.Int(status))
+			}
+			span.SetAttributes(AttrSuccess.Bool(err == nil))
+
+			var apiErr *vertex.APIError
+			if errors.As(err, &apiErr) {
+				span.SetAttributes(AttrMessage.String(apiErr.Message))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			} else {
+				span.SetAttributes(resultAttributes(resp.Data)...)
+			}
+
+			if duration != nil {
+				attrs := append(common, AttrSuccess.Bool(err == nil))
+				if status > 0 {
+					attrs = append(attrs, AttrStatusCode.Int(status))
+				}
+				duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+			}
+			return resp, err
+		}
+	}
+}
+
+// statusCode 从响应或错误中提取 HTTP 状态码
+func statusCode(resp *vertex.Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	var httpErr *vertex.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+// resultAttributes 统计返回数据的条数：数组取长度，分页对象取 torrents 长度与 total
+func resultAttributes(data json.RawMessage) []attribute.KeyValue {
+	if len(data) == 0 {
+		return nil
+	}
+	switch data[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err == nil {
+			return []attribute.KeyValue{AttrResultCount.Int(len(items))}
+		}
+	case '{':
+		var page struct {
+			Torrents []json.RawMessage `json:"torrents"`
+			Total    *int              `json:"total"`
+		}
+		if err := json.Unmarshal(data, &page); err == nil && page.Total != nil {
+			return []attribute.KeyValue{AttrResultCount.Int(len(page.Torrents)), AttrResultTotal.Int(*page.Total)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package otelvertex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/iniwex5/vertex-go-sdk"
+)
+
+// newTestClient 启动模拟的 Vertex 服务并返回带埋点的客户端
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*vertex.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client, err := vertex.NewClient(context.Background(), srv.URL,
+		vertex.WithoutRetry(),
+		vertex.WithMiddleware(Middleware(
+			WithTracerProvider(tp),
+			WithMeterProvider(mp),
+			WithPropagator(propagation.TraceContext{}),
+		)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, exporter, reader
+}
+
+func writeJSON(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(body))
+}
+
+func TestMiddlewareRecordsSpan(t *testing.T) {
+	var traceparent string
+	client, exporter, reader := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		writeJSON(w, `{"success":true,"data":[{"id":"s1"},{"id":"s2"}]}`)
+	})
+
+	servers, err := client.ListServers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("servers = %d, want 2", len(servers))
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "vertex.ListServers" {
+		t.Errorf("span name = %q", span.Name)
+	}
+	attrs := map[string]interface{}{}
+	for _, kv := range span.Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs[string(AttrEndpoint)] != "/api/server/list" {
+		t.Errorf("endpoint = %v", attrs[string(AttrEndpoint)])
+	}
+	if attrs[string(AttrStatusCode)] != int64(200) {
+		t.Errorf("status = %v", attrs[string(AttrStatusCode)])
+	}
+	if attrs[string(AttrSuccess)] != true {
+		t.Errorf("success = %v", attrs[string(AttrSuccess)])
+	}
+	if attrs[string(AttrResultCount)] != int64(2) {
+		t.Errorf("result count = %v", attrs[string(AttrResultCount)])
+	}
+	if traceparent == "" {
+		t.Error("traceparent header not injected")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if len(rm.ScopeMetrics) != 1 || len(rm.ScopeMetrics[0].Metrics) != 1 {
+		t.Fatalf("unexpected metrics: %+v", rm.ScopeMetrics)
+	}
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+	if !ok || len(hist.DataPoints) != 1 || hist.DataPoints[0].Count != 1 {
+		t.Fatalf("unexpected histogram: %+v", rm.ScopeMetrics[0].Metrics[0].Data)
+	}
+}
+
+func TestMiddlewareRecordsAPIError(t *testing.T) {
+	client, exporter, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"success":false,"message":"别名重复"}`)
+	})
+
+	if err := client.AddRss(context.Background(), vertex.RssConfig{Alias: "dup"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "vertex.AddRss" {
+		t.Errorf("span name = %q", span.Name)
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("status = %v, want Error", span.Status.Code)
+	}
+	found := false
+	for _, kv := range span.Attributes {
+		if kv.Key == AttrMessage && kv.Value.AsString() == "别名重复" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("vertex.message attribute missing")
+	}
+}
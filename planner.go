@@ -0,0 +1,517 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// 删种规划 (Delete Planner)
+// ==========================================
+
+// MainData 删种规则求值时的下载器整体状态，对应 Vertex 传给 JavaScript 规则的 maindata 参数
+type MainData struct {
+	FreeSpaceOnDisk int64 `json:"freeSpaceOnDisk"` // 剩余磁盘空间 (字节)
+	UploadSpeed     int64 `json:"uploadSpeed"`     // 下载器总上传速度 (B/s)
+	DownloadSpeed   int64 `json:"downloadSpeed"`   // 下载器总下载速度 (B/s)
+	LeechingCount   int   `json:"leechingCount"`   // 下载中数量
+	SeedingCount    int   `json:"seedingCount"`    // 做种中数量
+}
+
+// RuleEvaluator 执行 JavaScript 类型删种规则的求值器，code 形如 (maindata, torrent) => {...}。
+// SDK 本身不内置 JavaScript 引擎，可使用 jsrule 子模块提供的实现。
+type RuleEvaluator interface {
+	EvalDeleteRule(ctx context.Context, code string, main MainData, t *Torrent) (bool, error)
+}
+
+// FallbackStrategy 规则不足以达到目标空间时的补充删种顺序，less 返回 true 表示 a 应先于 b 删除
+type FallbackStrategy func(a, b *Torrent) bool
+
+// OldestFirst 优先删除最早添加的种子
+func OldestFirst(a, b *Torrent) bool {
+	return a.AddedTime < b.AddedTime
+}
+
+// LowestRatioFirst 优先删除分享率最低的种子
+func LowestRatioFirst(a, b *Torrent) bool {
+	return a.Ratio < b.Ratio
+}
+
+// SlowestUploadFirst 优先删除上传速度最慢的种子
+func SlowestUploadFirst(a, b *Torrent) bool {
+	return a.UploadSpeed < b.UploadSpeed
+}
+
+// PlanOption 删种规划选项
+type PlanOption struct {
+	DownloaderID    string           // 下载器 ID (必填)
+	FreeSpace       int64            // 当前剩余空间 (字节，必填且大于 0)，Vertex 接口未提供，需由调用方给出
+	TargetFreeSpace int64            // 目标剩余空间 (字节)
+	Evaluator       RuleEvaluator    // JavaScript 规则求值器，为 nil 时跳过 JavaScript 规则
+	Fallback        FallbackStrategy // 规则删除后仍未达到目标时的补充策略，为 nil 时不补充
+	Now             time.Time        // 模拟的当前时间，为零值时使用 time.Now()
+}
+
+// PlanItem 规划删除的单个种子
+type PlanItem struct {
+	Torrent        Torrent
+	Rule           *DeleteRule // 命中的删种规则，补充策略选中时为 nil
+	FreedBytes     int64       // 预计释放的空间
+	FreeSpaceAfter int64       // 删除后的剩余空间
+}
+
+// SkippedRule 无法在本地求值的规则
+type SkippedRule struct {
+	Rule   DeleteRule
+	Reason string
+}
+
+// DeletePlan 删种规划结果
+type DeletePlan struct {
+	DownloaderID    string
+	FreeSpaceBefore int64         // 规划前剩余空间
+	FreeSpaceAfter  int64         // 规划后预计剩余空间
+	TargetFreeSpace int64         // 目标剩余空间
+	Items           []PlanItem    // 按删除顺序排列
+	Skipped         []SkippedRule // 被跳过的规则
+}
+
+// Reached 规划执行后是否达到目标剩余空间
+func (p *DeletePlan) Reached() bool {
+	return p.FreeSpaceAfter >= p.TargetFreeSpace
+}
+
+// FreedBytes 预计释放的总空间
+func (p *DeletePlan) FreedBytes() int64 {
+	return p.FreeSpaceAfter - p.FreeSpaceBefore
+}
+
+// validate 检查规划选项。剩余空间为 0 通常意味着调用方忘记设置，此时 "剩余空间小于 X" 类规则会全部命中
+func (o *PlanOption) validate() error {
+	if o.FreeSpace <= 0 {
+		return fmt.Errorf("PlanOption.FreeSpace 未设置或无效: %d", o.FreeSpace)
+	}
+	if o.TargetFreeSpace < 0 {
+		return fmt.Errorf("PlanOption.TargetFreeSpace 不能为负数: %d", o.TargetFreeSpace)
+	}
+	return nil
+}
+
+// PlanDeletions 读取下载器绑定的删种规则与种子列表，在本地模拟删种并给出规划 (不会实际删除任何种子)
+func (c *Client) PlanDeletions(ctx context.Context, opt PlanOption) (*DeletePlan, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	downloaders, err := c.ListDownloaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var downloader *DownloaderInfo
+	for i := range downloaders {
+		if downloaders[i].ID == opt.DownloaderID {
+			downloader = &downloaders[i]
+			break
+		}
+	}
+	if downloader == nil {
+		return nil, fmt.Errorf("未找到下载器: %s", opt.DownloaderID)
+	}
+
+	rules, err := c.ListDeleteRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	torrents, err := c.ListAllTorrents(ctx, TorrentListOption{ClientList: []string{opt.DownloaderID}})
+	if err != nil {
+		return nil, err
+	}
+	return PlanDeletions(ctx, *downloader, rules, torrents, opt)
+}
+
+// PlanDeletions 根据给定的下载器、删种规则与种子列表模拟删种，不发起任何请求。
+//
+// 模拟过程与 Vertex 一致：下载器 DeleteRules 中的规则按优先级从高到低依次对每个种子求值，
+// 命中任一 RejectDeleteRules 规则的种子受保护；每删除一个种子都会更新 maindata 中的剩余空间，
+// 因此 "剩余空间小于 X" 这类规则会在空间足够后停止命中。
+func PlanDeletions(ctx context.Context, downloader DownloaderInfo, rules []DeleteRule, torrents []Torrent, opt PlanOption) (*DeletePlan, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	now := opt.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	byID := make(map[string]DeleteRule, len(rules))
+	for _, r := range rules {
+		byID[r.ID] = r
+	}
+	active := pickRules(byID, downloader.DeleteRules)
+	reject := pickRules(byID, downloader.RejectDeleteRules)
+	sort.SliceStable(active, func(i, j int) bool {
+		return rulePriority(active[i]) > rulePriority(active[j])
+	})
+
+	plan := &DeletePlan{
+		DownloaderID:    downloader.ID,
+		FreeSpaceBefore: opt.FreeSpace,
+		FreeSpaceAfter:  opt.FreeSpace,
+		TargetFreeSpace: opt.TargetFreeSpace,
+	}
+	main := MainData{
+		FreeSpaceOnDisk: opt.FreeSpace,
+		UploadSpeed:     int64(downloader.UploadSpeed),
+		DownloadSpeed:   int64(downloader.DownloadSpeed),
+		LeechingCount:   downloader.LeechingCount,
+		SeedingCount:    downloader.SeedingCount,
+	}
+
+	skipped := make(map[string]bool)
+	skip := func(r DeleteRule, err error) {
+		if !skipped[r.ID] {
+			skipped[r.ID] = true
+			plan.Skipped = append(plan.Skipped, SkippedRule{Rule: r, Reason: err.Error()})
+		}
+	}
+	eval := func(r DeleteRule, t *Torrent) bool {
+		if skipped[r.ID] {
+			return false
+		}
+		ok, err := evalDeleteRule(ctx, r, main, t, now, opt.Evaluator)
+		if err != nil {
+			skip(r, err)
+			return false
+		}
+		return ok
+	}
+
+	deleted := make([]bool, len(torrents))
+	remove := func(i int, rule *DeleteRule) {
+		t := &torrents[i]
+		freed := diskUsage(t)
+		deleted[i] = true
+		main.FreeSpaceOnDisk += freed
+		if t.State.IsSeeding() {
+			main.SeedingCount--
+		} else if t.State.IsDownloading() {
+			main.LeechingCount--
+		}
+		plan.Items = append(plan.Items, PlanItem{Torrent: *t, Rule: rule, FreedBytes: freed, FreeSpaceAfter: main.FreeSpaceOnDisk})
+	}
+
+	protected := make([]bool, len(torrents))
+	for i := range torrents {
+		for _, r := range reject {
+			if eval(r, &torrents[i]) {
+				protected[i] = true
+				break
+			}
+		}
+	}
+
+	for ri := range active {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i := range torrents {
+			if deleted[i] || protected[i] {
+				continue
+			}
+			if eval(active[ri], &torrents[i]) {
+				remove(i, &active[ri])
+			}
+		}
+	}
+
+	if opt.Fallback != nil && main.FreeSpaceOnDisk < opt.TargetFreeSpace {
+		var rest []int
+		for i := range torrents {
+			if !deleted[i] && !protected[i] {
+				rest = append(rest, i)
+			}
+		}
+		sort.SliceStable(rest, func(a, b int) bool {
+			return opt.Fallback(&torrents[rest[a]], &torrents[rest[b]])
+		})
+		for _, i := range rest {
+			if main.FreeSpaceOnDisk >= opt.TargetFreeSpace {
+				break
+			}
+			remove(i, nil)
+		}
+	}
+
+	plan.FreeSpaceAfter = main.FreeSpaceOnDisk
+	return plan, nil
+}
+
+// pickRules 按 ID 取出规则，忽略不存在的 ID
+func pickRules(byID map[string]DeleteRule, ids []string) []DeleteRule {
+	var out []DeleteRule
+	for _, id := range ids {
+		if r, ok := byID[id]; ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// rulePriority 解析规则优先级 (数字或字符串)，无法解析时视为 0
+func rulePriority(r DeleteRule) float64 {
+	switch v := r.Priority.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f
+	}
+	return 0
+}
+
+// diskUsage 估算种子占用的磁盘空间 (大小 × 进度)
+func diskUsage(t *Torrent) int64 {
+	switch {
+	case t.Progress >= 1:
+		return t.Size
+	case t.Progress <= 0:
+		return 0
+	}
+	return int64(float64(t.Size) * t.Progress)
+}
+
+// evalDeleteRule 对单个种子求值删种规则
+func evalDeleteRule(ctx context.Context, r DeleteRule, main MainData, t *Torrent, now time.Time, js RuleEvaluator) (bool, error) {
+	if r.Type == string(RuleTypeJavaScript) {
+		if js == nil {
+			return false, errors.New("JavaScript 规则需要配置 Evaluator")
+		}
+		return js.EvalDeleteRule(ctx, r.Code, main, t)
+	}
+	return MatchDeleteConditions(r.Conditions, main, t, now)
+}
+
+// MatchDeleteConditions 判断种子是否满足全部删种条件 (Normal 类型规则)。
+//
+// 条件的字段与 Vertex 一致：种子字段 (name、size、ratio、uploadSpeed、category、tags、tracker 等)，
+// addedTime / completedTime 表示距今的秒数，以及 maindata 中的 freeSpace、leechingCount、
+// seedingCount、globalUploadSpeed、globalDownloadSpeed。数值比较的 Value 可以是算术表达式，如 "1024*1024"。
+func MatchDeleteConditions(conds []DeleteRuleCondition, main MainData, t *Torrent, now time.Time) (bool, error) {
+	if len(conds) == 0 {
+		return false, nil
+	}
+	for _, cond := range conds {
+		if cond.Key == "" {
+			continue
+		}
+		ok, err := matchCondition(cond, main, t, now)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchCondition(cond DeleteRuleCondition, main MainData, t *Torrent, now time.Time) (bool, error) {
+	value, err := conditionField(cond.Key, main, t, now)
+	if err != nil {
+		return false, err
+	}
+	if n, ok := value.(float64); ok && math.IsNaN(n) {
+		// 时间未知时条件不成立 (包括 notContain 等取反的比较)
+		return false, nil
+	}
+
+	switch CompareType(cond.CompareType) {
+	case CompareTypeBigger, CompareTypeSmaller:
+		n, ok := value.(float64)
+		if !ok {
+			return false, fmt.Errorf("字段 %s 不是数值，不支持 %s", cond.Key, cond.CompareType)
+		}
+		target, err := evalArithmetic(cond.Value)
+		if err != nil {
+			return false, fmt.Errorf("条件 %s 的值 %q 无效: %w", cond.Key, cond.Value, err)
+		}
+		if CompareType(cond.CompareType) == CompareTypeBigger {
+			return n > target, nil
+		}
+		return n < target, nil
+	case CompareTypeEquals:
+		if n, ok := value.(float64); ok {
+			target, err := evalArithmetic(cond.Value)
+			if err != nil {
+				return false, fmt.Errorf("条件 %s 的值 %q 无效: %w", cond.Key, cond.Value, err)
+			}
+			return n == target, nil
+		}
+		return value.(string) == cond.Value, nil
+	}
+
+	s := fmt.Sprint(value)
+	if n, ok := value.(float64); ok {
+		s = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	switch CompareType(cond.CompareType) {
+	case CompareTypeContain:
+		return strings.Contains(s, cond.Value), nil
+	case CompareTypeNotContain:
+		return !strings.Contains(s, cond.Value), nil
+	case CompareTypeIncludeIn, CompareTypeNotIncludeIn:
+		in := false
+		for _, item := range strings.Split(cond.Value, ",") {
+			if strings.TrimSpace(item) == s {
+				in = true
+				break
+			}
+		}
+		return in == (CompareType(cond.CompareType) == CompareTypeIncludeIn), nil
+	case CompareTypeRegExp, CompareTypeNotRegExp:
+		re, err := regexp.Compile(cond.Value)
+		if err != nil {
+			return false, fmt.Errorf("条件 %s 的正则表达式无效: %w", cond.Key, err)
+		}
+		return re.MatchString(s) == (CompareType(cond.CompareType) == CompareTypeRegExp), nil
+	}
+	return false, fmt.Errorf("未知的比较类型: %s", cond.CompareType)
+}
+
+// conditionField 返回条件字段的值，数值为 float64 (时间未知时为 NaN)，其余为 string
+func conditionField(key string, main MainData, t *Torrent, now time.Time) (interface{}, error) {
+	switch key {
+	case "name":
+		return t.Name, nil
+	case "hash":
+		return t.Hash, nil
+	case "category":
+		return t.Category, nil
+	case "tags":
+		return strings.Join(t.Tags, ","), nil
+	case "tracker":
+		return t.Tracker, nil
+	case "savePath":
+		return t.SavePath, nil
+	case "state":
+		return string(t.State), nil
+	case "size":
+		return float64(t.Size), nil
+	case "progress":
+		return t.Progress, nil
+	case "ratio":
+		return t.Ratio, nil
+	case "uploaded":
+		return float64(t.Uploaded), nil
+	case "downloaded":
+		return float64(t.Downloaded), nil
+	case "uploadSpeed":
+		return float64(t.UploadSpeed), nil
+	case "downloadSpeed":
+		return float64(t.DownloadSpeed), nil
+	case "seeder":
+		return float64(t.Seeders), nil
+	case "leecher":
+		return float64(t.Leechers), nil
+	case "addedTime":
+		return sinceSeconds(t.AddedTime, now), nil
+	case "completedTime":
+		return sinceSeconds(t.CompletedTime, now), nil
+	case "freeSpace":
+		return float64(main.FreeSpaceOnDisk), nil
+	case "leechingCount":
+		return float64(main.LeechingCount), nil
+	case "seedingCount":
+		return float64(main.SeedingCount), nil
+	case "globalUploadSpeed":
+		return float64(main.UploadSpeed), nil
+	case "globalDownloadSpeed":
+		return float64(main.DownloadSpeed), nil
+	}
+	return nil, fmt.Errorf("不支持在本地求值的条件字段: %s", key)
+}
+
+// evalArithmetic 计算只包含数字、+ - * / 与括号的表达式，如 "1024*1024*10"
+func evalArithmetic(expr string) (float64, error) {
+	p := arithParser{s: strings.ReplaceAll(expr, " ", "")}
+	v, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos != len(p.s) {
+		return 0, fmt.Errorf("无法解析 %q", p.s[p.pos:])
+	}
+	return v, nil
+}
+
+type arithParser struct {
+	s   string
+	pos int
+}
+
+func (p *arithParser) sum() (float64, error) {
+	v, err := p.product()
+	for err == nil && p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+		op := p.s[p.pos]
+		p.pos++
+		var r float64
+		if r, err = p.product(); op == '+' {
+			v += r
+		} else {
+			v -= r
+		}
+	}
+	return v, err
+}
+
+func (p *arithParser) product() (float64, error) {
+	v, err := p.unary()
+	for err == nil && p.pos < len(p.s) && (p.s[p.pos] == '*' || p.s[p.pos] == '/') {
+		op := p.s[p.pos]
+		p.pos++
+		var r float64
+		if r, err = p.unary(); op == '*' {
+			v *= r
+		} else if r == 0 {
+			err = errors.New("除数为 0")
+		} else {
+			v /= r
+		}
+	}
+	return v, err
+}
+
+func (p *arithParser) unary() (float64, error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '-' {
+		p.pos++
+		v, err := p.unary()
+		return -v, err
+	}
+	if p.pos < len(p.s) && p.s[p.pos] == '(' {
+		p.pos++
+		v, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+		if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+			return 0, errors.New("缺少右括号")
+		}
+		p.pos++
+		return v, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] >= '0' && p.s[p.pos] <= '9' || p.s[p.pos] == '.') {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, errors.New("此处需要数字")
+	}
+	return strconv.ParseFloat(p.s[start:p.pos], 64)
+}
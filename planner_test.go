@@ -0,0 +1,214 @@
+package vertex
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEvalArithmetic(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"42", 42},
+		{"1.5", 1.5},
+		{"1024*1024*10", 10 << 20},
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"12 / 4 / 3", 1},
+		{"-5 + 2", -3},
+		{"-(2 + 3) * 2", -10},
+		{"2 * -3", -6},
+		{"((4))", 4},
+	}
+	for _, tt := range tests {
+		got, err := evalArithmetic(tt.expr)
+		if err != nil {
+			t.Errorf("evalArithmetic(%q) 出错: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("evalArithmetic(%q) = %v, 期望 %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "1 +", "1 / 0", "1 / (2 - 2)", "(1 + 2", "1 + 2)", "abc", "1.2.3", "3 % 2"} {
+		if v, err := evalArithmetic(expr); err == nil {
+			t.Errorf("evalArithmetic(%q) 期望出错，实际: %v", expr, v)
+		}
+	}
+}
+
+func TestMatchDeleteConditions(t *testing.T) {
+	now := time.Now()
+	torrent := &Torrent{
+		Name:          "Movie.2020.1080p",
+		Category:      "movies",
+		Tags:          []string{"hd", "keep"},
+		State:         StateSeeding,
+		Size:          10 << 30,
+		Ratio:         1.5,
+		UploadSpeed:   1024,
+		AddedTime:     now.Add(-48 * time.Hour).Unix(),
+		CompletedTime: 0, // 完成时间未知
+	}
+	main := MainData{FreeSpaceOnDisk: 5 << 30, SeedingCount: 3}
+	cond := func(key string, ct CompareType, value string) DeleteRuleCondition {
+		return DeleteRuleCondition{Key: key, CompareType: string(ct), Value: value}
+	}
+
+	tests := []struct {
+		name  string
+		conds []DeleteRuleCondition
+		want  bool
+	}{
+		{"无条件不命中", nil, false},
+		{"空字段忽略", []DeleteRuleCondition{{}, cond("ratio", CompareTypeBigger, "1")}, true},
+		{"大于", []DeleteRuleCondition{cond("size", CompareTypeBigger, "1024*1024*1024")}, true},
+		{"小于", []DeleteRuleCondition{cond("freeSpace", CompareTypeSmaller, "4*1024*1024*1024")}, false},
+		{"数值等于", []DeleteRuleCondition{cond("seedingCount", CompareTypeEquals, "3")}, true},
+		{"字符串等于", []DeleteRuleCondition{cond("category", CompareTypeEquals, "movies")}, true},
+		{"包含", []DeleteRuleCondition{cond("name", CompareTypeContain, "1080p")}, true},
+		{"不包含", []DeleteRuleCondition{cond("tags", CompareTypeNotContain, "keep")}, false},
+		{"包含于", []DeleteRuleCondition{cond("state", CompareTypeIncludeIn, "downloading, seeding")}, true},
+		{"不包含于", []DeleteRuleCondition{cond("category", CompareTypeNotIncludeIn, "tv,music")}, true},
+		{"正则", []DeleteRuleCondition{cond("name", CompareTypeRegExp, `\.(19|20)\d\d\.`)}, true},
+		{"正则不匹配", []DeleteRuleCondition{cond("name", CompareTypeNotRegExp, `720p`)}, true},
+		{"数值转字符串比较", []DeleteRuleCondition{cond("ratio", CompareTypeContain, "1.5")}, true},
+		{"全部满足", []DeleteRuleCondition{cond("addedTime", CompareTypeBigger, "86400"), cond("uploadSpeed", CompareTypeSmaller, "2048")}, true},
+		{"任一不满足", []DeleteRuleCondition{cond("addedTime", CompareTypeBigger, "86400"), cond("uploadSpeed", CompareTypeBigger, "2048")}, false},
+		// 时间未知时，无论比较方式如何都不命中
+		{"未知时间大于", []DeleteRuleCondition{cond("completedTime", CompareTypeBigger, "0")}, false},
+		{"未知时间小于", []DeleteRuleCondition{cond("completedTime", CompareTypeSmaller, "86400")}, false},
+		{"未知时间不包含", []DeleteRuleCondition{cond("completedTime", CompareTypeNotContain, "1")}, false},
+		{"未知时间不包含于", []DeleteRuleCondition{cond("completedTime", CompareTypeNotIncludeIn, "1,2")}, false},
+	}
+	for _, tt := range tests {
+		got, err := MatchDeleteConditions(tt.conds, main, torrent, now)
+		if err != nil {
+			t.Errorf("%s: 出错: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: 得到 %v, 期望 %v", tt.name, got, tt.want)
+		}
+	}
+
+	errCases := []struct {
+		name  string
+		conds []DeleteRuleCondition
+	}{
+		{"未知字段", []DeleteRuleCondition{cond("nope", CompareTypeEquals, "1")}},
+		{"未知比较类型", []DeleteRuleCondition{cond("name", "like", "x")}},
+		{"字符串字段数值比较", []DeleteRuleCondition{cond("name", CompareTypeBigger, "1")}},
+		{"无效表达式", []DeleteRuleCondition{cond("size", CompareTypeBigger, "1GiB")}},
+		{"无效正则", []DeleteRuleCondition{cond("name", CompareTypeRegExp, "(")}},
+	}
+	for _, tt := range errCases {
+		if _, err := MatchDeleteConditions(tt.conds, main, torrent, now); err == nil {
+			t.Errorf("%s: 期望出错", tt.name)
+		}
+	}
+}
+
+func TestConditionFieldUnknownTime(t *testing.T) {
+	now := time.Now()
+	v, err := conditionField("addedTime", MainData{}, &Torrent{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := v.(float64); !ok || !math.IsNaN(n) {
+		t.Fatalf("未知添加时间应为 NaN，实际: %v", v)
+	}
+	v, _ = conditionField("addedTime", MainData{}, &Torrent{AddedTime: now.Add(-time.Hour).Unix()}, now)
+	if n := v.(float64); n < 3599 || n > 3601 {
+		t.Fatalf("添加时间距今应约为 3600 秒，实际: %v", n)
+	}
+}
+
+func TestPlanDeletionsFreeSpace(t *testing.T) {
+	ctx := context.Background()
+	for _, opt := range []PlanOption{
+		{},
+		{FreeSpace: -1},
+		{FreeSpace: 1 << 30, TargetFreeSpace: -1},
+	} {
+		if _, err := PlanDeletions(ctx, DownloaderInfo{}, nil, nil, opt); err == nil {
+			t.Errorf("选项 %+v 期望出错", opt)
+		}
+	}
+
+	// 参数校验在发起任何请求之前完成
+	c, srv := newTestClient(t, nil)
+	if _, err := c.PlanDeletions(ctx, PlanOption{DownloaderID: "d1"}); err == nil {
+		t.Fatal("未设置 FreeSpace 时期望出错")
+	}
+	if n := srv.Calls("/api/downloader/list"); n != 0 {
+		t.Fatalf("参数无效时不应发起请求，实际请求 %d 次", n)
+	}
+}
+
+func TestPlanDeletions(t *testing.T) {
+	now := time.Now()
+	downloader := DownloaderInfo{DownloaderConfig: DownloaderConfig{
+		ID:                "d1",
+		DeleteRules:       []string{"low", "high", "missing"},
+		RejectDeleteRules: []string{"keep"},
+	}}
+	rules := []DeleteRule{
+		{ID: "high", Priority: "10", Conditions: []DeleteRuleCondition{
+			{Key: "freeSpace", CompareType: string(CompareTypeSmaller), Value: "3*1024*1024*1024"},
+			{Key: "ratio", CompareType: string(CompareTypeBigger), Value: "2"},
+		}},
+		{ID: "low", Priority: 1.0, Conditions: []DeleteRuleCondition{
+			{Key: "addedTime", CompareType: string(CompareTypeBigger), Value: "86400"},
+		}},
+		{ID: "keep", Conditions: []DeleteRuleCondition{
+			{Key: "tags", CompareType: string(CompareTypeContain), Value: "keep"},
+		}},
+		{ID: "js", Type: string(RuleTypeJavaScript), Code: "() => true"},
+	}
+	day := int64(86400)
+	torrents := []Torrent{
+		{Hash: "a", Size: 1 << 30, Progress: 1, Ratio: 3, AddedTime: now.Unix() - 3600},
+		{Hash: "b", Size: 1 << 30, Progress: 1, Ratio: 5, AddedTime: now.Unix() - 3600},
+		{Hash: "c", Size: 4 << 30, Progress: 0.5, Ratio: 0.1, AddedTime: now.Unix() - 3*day},
+		{Hash: "d", Size: 8 << 30, Progress: 1, Ratio: 9, AddedTime: now.Unix() - 3*day, Tags: []string{"keep"}},
+		{Hash: "e", Size: 1 << 30, Progress: 1, Ratio: 0.2}, // 添加时间未知，不应被 "low" 命中
+	}
+
+	plan, err := PlanDeletions(context.Background(), downloader, rules, torrents, PlanOption{
+		FreeSpace:       1 << 30,
+		TargetFreeSpace: 6 << 30,
+		Fallback:        LowestRatioFirst,
+		Now:             now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, item := range plan.Items {
+		got = append(got, item.Torrent.Hash)
+	}
+	// high 规则先求值：删除 a 后剩余 2GiB，仍小于 3GiB，继续删除 b；
+	// low 规则删除 c (释放一半)；e 由补充策略删除；d 受保护
+	want := []string{"a", "b", "c", "e"}
+	if len(got) != len(want) {
+		t.Fatalf("删除顺序: %v, 期望 %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("删除顺序: %v, 期望 %v", got, want)
+		}
+	}
+	if plan.Items[3].Rule != nil {
+		t.Fatalf("补充策略删除的种子不应关联规则: %+v", plan.Items[3].Rule)
+	}
+	if plan.FreeSpaceAfter != 6<<30 || !plan.Reached() || plan.FreedBytes() != 5<<30 {
+		t.Fatalf("剩余空间不符合预期: %+v", plan)
+	}
+}
@@ -0,0 +1,203 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ==========================================
+// 多实例管理 (Pool)
+// ==========================================
+
+// Pool 管理多个命名的 Vertex 客户端，每个客户端拥有独立的 Cookie 与认证信息
+type Pool struct {
+	mu          sync.RWMutex
+	clients     map[string]*Client
+	concurrency int
+}
+
+// PoolOption 是用于配置 Pool 的函数选项模式
+type PoolOption func(*Pool)
+
+// WithPoolConcurrency 配置跨实例操作的最大并发数 (默认 4)
+func WithPoolConcurrency(n int) PoolOption {
+	return func(p *Pool) {
+		p.concurrency = n
+	}
+}
+
+// NewPool 创建一个空的客户端池
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{
+		clients:     make(map[string]*Client),
+		concurrency: 4,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Add 以指定名称加入一个已创建的客户端，同名客户端会被替换
+func (p *Pool) Add(name string, c *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clients[name] = c
+}
+
+// Connect 创建新的客户端并以指定名称加入池中
+func (p *Pool) Connect(ctx context.Context, name, host string, opts ...ClientOption) error {
+	c, err := NewClient(ctx, host, opts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	p.Add(name, c)
+	return nil
+}
+
+// Remove 移除指定名称的客户端
+func (p *Pool) Remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, name)
+}
+
+// Get 获取指定名称的客户端
+func (p *Pool) Get(name string) (*Client, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	c, ok := p.clients[name]
+	return c, ok
+}
+
+// Names 返回池中所有实例名称 (已排序)
+func (p *Pool) Names() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	names := make([]string, 0, len(p.clients))
+	for name := range p.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Do 在所有实例上并发执行 fn (并发数受 WithPoolConcurrency 限制)，返回出错实例的错误
+func (p *Pool) Do(ctx context.Context, fn func(ctx context.Context, name string, c *Client) error) map[string]error {
+	res := PoolCollect(ctx, p, func(ctx context.Context, name string, c *Client) (struct{}, error) {
+		return struct{}{}, fn(ctx, name, c)
+	})
+	return res.Errors
+}
+
+// PoolResult 跨实例操作的聚合结果
+type PoolResult[T any] struct {
+	Values map[string]T     // 成功实例的结果，以实例名称为键
+	Errors map[string]error // 失败实例的错误，以实例名称为键
+}
+
+// Err 将所有实例的错误合并为一个错误，全部成功时返回 nil
+func (r *PoolResult[T]) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(r.Errors))
+	for name := range r.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("%s: %w", name, r.Errors[name]))
+	}
+	return errors.Join(errs...)
+}
+
+// PoolCollect 在所有实例上并发执行 fn 并聚合结果
+func PoolCollect[T any](ctx context.Context, p *Pool, fn func(ctx context.Context, name string, c *Client) (T, error)) *PoolResult[T] {
+	p.mu.RLock()
+	names := make([]string, 0, len(p.clients))
+	clients := make([]*Client, 0, len(p.clients))
+	for name, c := range p.clients {
+		names = append(names, name)
+		clients = append(clients, c)
+	}
+	limit := p.concurrency
+	p.mu.RUnlock()
+
+	res := &PoolResult[T]{
+		Values: make(map[string]T),
+		Errors: make(map[string]error),
+	}
+	var mu sync.Mutex
+	parallel(len(names), limit, func(i int) {
+		var (
+			v   T
+			err = ctx.Err()
+		)
+		if err == nil {
+			v, err = fn(ctx, names[i], clients[i])
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			res.Errors[names[i]] = err
+			return
+		}
+		res.Values[names[i]] = v
+	})
+	return res
+}
+
+// ListServers 获取所有实例的服务器列表
+func (p *Pool) ListServers(ctx context.Context) *PoolResult[[]Server] {
+	return PoolCollect(ctx, p, func(ctx context.Context, _ string, c *Client) ([]Server, error) {
+		return c.ListServers(ctx)
+	})
+}
+
+// ListDownloaders 获取所有实例的下载器列表
+func (p *Pool) ListDownloaders(ctx context.Context) *PoolResult[[]DownloaderInfo] {
+	return PoolCollect(ctx, p, func(ctx context.Context, _ string, c *Client) ([]DownloaderInfo, error) {
+		return c.ListDownloaders(ctx)
+	})
+}
+
+// ListRss 获取所有实例的 RSS 任务列表
+func (p *Pool) ListRss(ctx context.Context) *PoolResult[[]RssConfig] {
+	return PoolCollect(ctx, p, func(ctx context.Context, _ string, c *Client) ([]RssConfig, error) {
+		return c.ListRss(ctx)
+	})
+}
+
+// ListAllTorrents 获取所有实例的全部种子
+func (p *Pool) ListAllTorrents(ctx context.Context, opt TorrentListOption) *PoolResult[[]Torrent] {
+	return PoolCollect(ctx, p, func(ctx context.Context, _ string, c *Client) ([]Torrent, error) {
+		return c.ListAllTorrents(ctx, opt)
+	})
+}
+
+// parallel 以最多 limit 个并发执行 fn(0) ~ fn(n-1)，并等待全部完成。
+// fn 需要自行检查 ctx，以便取消后剩余任务能尽快返回。
+func parallel(n, limit int, fn func(i int)) {
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,230 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// peakCounter 记录同时执行的最大数量
+type peakCounter struct {
+	inFlight, peak int32
+}
+
+func (p *peakCounter) enter() {
+	n := atomic.AddInt32(&p.inFlight, 1)
+	for {
+		peak := atomic.LoadInt32(&p.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&p.peak, peak, n) {
+			return
+		}
+	}
+}
+
+func (p *peakCounter) leave() {
+	atomic.AddInt32(&p.inFlight, -1)
+}
+
+func (p *peakCounter) max() int {
+	return int(atomic.LoadInt32(&p.peak))
+}
+
+func TestParallel(t *testing.T) {
+	for _, tt := range []struct {
+		n, limit, want int
+	}{
+		{10, 3, 3},
+		{5, 0, 5},   // 未设置时不限制
+		{4, 100, 4}, // 上限超过任务数
+		{0, 2, 0},
+	} {
+		var counter peakCounter
+		var mu sync.Mutex
+		seen := make(map[int]int)
+		parallel(tt.n, tt.limit, func(i int) {
+			counter.enter()
+			defer counter.leave()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			seen[i]++
+			mu.Unlock()
+		})
+		if len(seen) != tt.n {
+			t.Errorf("n=%d: 执行了 %d 个任务", tt.n, len(seen))
+		}
+		for i, c := range seen {
+			if c != 1 {
+				t.Errorf("n=%d: 任务 %d 执行了 %d 次", tt.n, i, c)
+			}
+		}
+		if counter.max() != tt.want {
+			t.Errorf("n=%d limit=%d: 最大并发 %d, 期望 %d", tt.n, tt.limit, counter.max(), tt.want)
+		}
+	}
+}
+
+// newTestPool 创建包含 names 中各实例的客户端池，客户端不会发起请求
+func newTestPool(names []string, opts ...PoolOption) *Pool {
+	p := NewPool(opts...)
+	for _, name := range names {
+		p.Add(name, &Client{BaseURL: "http://" + name})
+	}
+	return p
+}
+
+func TestPoolCollectConcurrency(t *testing.T) {
+	names := make([]string, 10)
+	for i := range names {
+		names[i] = fmt.Sprintf("v%02d", i)
+	}
+	for _, tt := range []struct {
+		opts []PoolOption
+		want int
+	}{
+		{nil, 4}, // 默认并发数
+		{[]PoolOption{WithPoolConcurrency(2)}, 2},
+		{[]PoolOption{WithPoolConcurrency(0)}, len(names)},
+	} {
+		var counter peakCounter
+		res := PoolCollect(context.Background(), newTestPool(names, tt.opts...), func(ctx context.Context, name string, c *Client) (string, error) {
+			counter.enter()
+			defer counter.leave()
+			time.Sleep(10 * time.Millisecond)
+			return c.BaseURL, nil
+		})
+		if counter.max() != tt.want {
+			t.Errorf("最大并发 %d, 期望 %d", counter.max(), tt.want)
+		}
+		if len(res.Values) != len(names) || res.Values["v03"] != "http://v03" {
+			t.Errorf("结果不符合预期: %v", res.Values)
+		}
+	}
+}
+
+func TestPoolCollectPartialFailure(t *testing.T) {
+	p := newTestPool([]string{"c", "a", "b", "d"})
+	errB := errors.New("b 离线")
+	res := PoolCollect(context.Background(), p, func(ctx context.Context, name string, c *Client) (int, error) {
+		switch name {
+		case "b":
+			return 0, errB
+		case "d":
+			return 0, errors.New("d 认证失败")
+		}
+		return len(c.BaseURL), nil
+	})
+
+	if len(res.Values) != 2 || res.Values["a"] != len("http://a") || res.Values["c"] != len("http://c") {
+		t.Fatalf("成功实例的结果不符合预期: %v", res.Values)
+	}
+	if _, ok := res.Values["b"]; ok {
+		t.Fatal("失败实例不应出现在 Values 中")
+	}
+	if len(res.Errors) != 2 || res.Errors["b"] != errB {
+		t.Fatalf("失败实例的错误不符合预期: %v", res.Errors)
+	}
+
+	err := res.Err()
+	if !errors.Is(err, errB) {
+		t.Fatalf("合并后的错误应包含各实例的错误: %v", err)
+	}
+	// 按实例名称排序，输出稳定
+	if want := "b: b 离线\nd: d 认证失败"; err.Error() != want {
+		t.Fatalf("错误信息为 %q, 期望 %q", err.Error(), want)
+	}
+	if (&PoolResult[int]{}).Err() != nil {
+		t.Fatal("没有错误时 Err 应返回 nil")
+	}
+}
+
+func TestPoolCollectCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := newTestPool([]string{"a", "b", "c", "d"}, WithPoolConcurrency(1))
+
+	var called []string
+	res := PoolCollect(ctx, p, func(ctx context.Context, name string, c *Client) (string, error) {
+		called = append(called, name)
+		cancel()
+		return name, nil
+	})
+	// 并发数为 1 时，取消后剩余实例不再执行
+	if len(called) != 1 {
+		t.Fatalf("取消后不应继续执行，实际执行: %v", called)
+	}
+	if len(res.Values) != 1 || len(res.Errors) != 3 {
+		t.Fatalf("结果不符合预期: %v / %v", res.Values, res.Errors)
+	}
+	for name, err := range res.Errors {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s 应记录为取消错误，实际: %v", name, err)
+		}
+	}
+}
+
+func TestPoolManage(t *testing.T) {
+	p := newTestPool([]string{"b", "a"})
+	if got := strings.Join(p.Names(), ","); got != "a,b" {
+		t.Fatalf("Names() = %s", got)
+	}
+	replaced := &Client{BaseURL: "http://new"}
+	p.Add("a", replaced)
+	if c, ok := p.Get("a"); !ok || c != replaced {
+		t.Fatal("同名客户端应被替换")
+	}
+	p.Remove("b")
+	if _, ok := p.Get("b"); ok {
+		t.Fatal("客户端应已移除")
+	}
+	if got := strings.Join(p.Names(), ","); got != "a" {
+		t.Fatalf("Names() = %s", got)
+	}
+}
+
+func TestPoolDo(t *testing.T) {
+	p := newTestPool([]string{"a", "b", "c"})
+	var calls int32
+	errs := p.Do(context.Background(), func(ctx context.Context, name string, c *Client) error {
+		atomic.AddInt32(&calls, 1)
+		if name == "b" {
+			return errors.New("失败")
+		}
+		return nil
+	})
+	if calls != 3 {
+		t.Fatalf("应在所有实例上执行，实际 %d 次", calls)
+	}
+	if len(errs) != 1 || errs["b"] == nil {
+		t.Fatalf("应只返回失败实例的错误: %v", errs)
+	}
+}
+
+func TestPoolListServers(t *testing.T) {
+	p := NewPool()
+	for _, name := range []string{"home", "seedbox"} {
+		name := name
+		c, _ := newTestClient(t, map[string]testRoute{
+			"/api/server/list": staticRoute([]map[string]interface{}{{"id": name + "-1", "alias": name}}),
+		})
+		p.Add(name, c)
+	}
+	broken, _ := newTestClient(t, map[string]testRoute{
+		"/api/server/list": func(*http.Request) (interface{}, error) { return nil, errors.New("未登录") },
+	})
+	p.Add("broken", broken)
+
+	res := p.ListServers(context.Background())
+	if len(res.Values) != 2 || res.Values["seedbox"][0].ID != "seedbox-1" {
+		t.Fatalf("成功实例的结果不符合预期: %v", res.Values)
+	}
+	var apiErr *APIError
+	if !errors.As(res.Errors["broken"], &apiErr) {
+		t.Fatalf("失败实例应保留业务错误: %v", res.Errors)
+	}
+}
@@ -0,0 +1,732 @@
+package vertex
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ==========================================
+// 种子查询语言 (Query)
+// ==========================================
+
+// 查询语法示例：
+//
+//	state=seeding and size>10GiB and uploadSpeed<1KiB order by size desc limit 20
+//	client in (qb1, qb2) and (ratio < 1 or age > 30d) and not tags = keep
+//	name ~ "1080p" and tracker ~ example.org
+//
+// 比较运算符：= != < <= > >= ~ (包含) !~ (不包含) in (值列表)。
+// 字符串比较不区分大小写；大小与速度支持 B/KB/MB/GB/TB (1000 进制) 与 KiB/MiB/GiB/TiB (1024 进制)；
+// 时长支持 s/m/h/d/w，如 30d。state=seeding/downloading/paused/error 按状态分组匹配，
+// 其他取值按原始状态精确匹配。
+
+// QueryError 查询语句解析错误
+type QueryError struct {
+	Pos int    // 出错位置 (字节偏移)
+	Msg string // 错误描述
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("查询语句错误 (位置 %d): %s", e.Pos, e.Msg)
+}
+
+// fieldKind 字段的取值类型
+type fieldKind int
+
+const (
+	kindString   fieldKind = iota // 字符串
+	kindBytes                     // 字节数 (支持单位)
+	kindNumber                    // 普通数值
+	kindDuration                  // 时长 (支持单位)
+	kindState                     // 种子状态
+	kindTags                      // 标签列表
+)
+
+// queryField 可用于查询与排序的字段
+type queryField struct {
+	kind    fieldKind
+	sortKey string // Vertex 服务端排序字段，为空表示不支持服务端排序
+	reverse bool   // 服务端排序方向与本字段相反 (如 age 越大 addedTime 越小)
+	str     func(t *Torrent) string
+	num     func(t *Torrent, now time.Time) float64
+}
+
+var queryFields = map[string]queryField{
+	"name":     {kind: kindString, sortKey: "name", str: func(t *Torrent) string { return t.Name }},
+	"hash":     {kind: kindString, str: func(t *Torrent) string { return t.Hash }},
+	"category": {kind: kindString, sortKey: "category", str: func(t *Torrent) string { return t.Category }},
+	"tracker":  {kind: kindString, sortKey: "tracker", str: func(t *Torrent) string { return t.Tracker }},
+	"savepath": {kind: kindString, sortKey: "savePath", str: func(t *Torrent) string { return t.SavePath }},
+	"client":   {kind: kindString, sortKey: "clientAlias", str: func(t *Torrent) string { return t.ClientAlias }},
+	"state":    {kind: kindState, sortKey: "state", str: func(t *Torrent) string { return string(t.State) }},
+	"tags":     {kind: kindTags},
+
+	"size":          {kind: kindBytes, sortKey: "size", num: func(t *Torrent, _ time.Time) float64 { return float64(t.Size) }},
+	"uploaded":      {kind: kindBytes, sortKey: "uploaded", num: func(t *Torrent, _ time.Time) float64 { return float64(t.Uploaded) }},
+	"downloaded":    {kind: kindBytes, sortKey: "downloaded", num: func(t *Torrent, _ time.Time) float64 { return float64(t.Downloaded) }},
+	"uploadspeed":   {kind: kindBytes, sortKey: "uploadSpeed", num: func(t *Torrent, _ time.Time) float64 { return float64(t.UploadSpeed) }},
+	"downloadspeed": {kind: kindBytes, sortKey: "downloadSpeed", num: func(t *Torrent, _ time.Time) float64 { return float64(t.DownloadSpeed) }},
+	"progress":      {kind: kindNumber, sortKey: "progress", num: func(t *Torrent, _ time.Time) float64 { return t.Progress }},
+	"ratio":         {kind: kindNumber, sortKey: "ratio", num: func(t *Torrent, _ time.Time) float64 { return t.Ratio }},
+	"seeders":       {kind: kindNumber, num: func(t *Torrent, _ time.Time) float64 { return float64(t.Seeders) }},
+	"leechers":      {kind: kindNumber, num: func(t *Torrent, _ time.Time) float64 { return float64(t.Leechers) }},
+	"age": {kind: kindDuration, sortKey: "addedTime", reverse: true, num: func(t *Torrent, now time.Time) float64 {
+		return sinceSeconds(t.AddedTime, now)
+	}},
+	"completedage": {kind: kindDuration, sortKey: "completedTime", reverse: true, num: func(t *Torrent, now time.Time) float64 {
+		return sinceSeconds(t.CompletedTime, now)
+	}},
+}
+
+// sinceSeconds 返回距离 now 的秒数，时间未知时返回 NaN (任何比较均不成立)
+func sinceSeconds(unix int64, now time.Time) float64 {
+	if unix <= 0 {
+		return math.NaN()
+	}
+	return now.Sub(time.Unix(unix, 0)).Seconds()
+}
+
+// Query 已解析的种子查询
+type Query struct {
+	src   string
+	expr  queryNode // 为 nil 表示匹配所有种子
+	order []queryOrder
+	limit int // 0 表示不限制
+
+	searchKey string   // 可下推给服务端的名称关键词
+	clients   []string // 可下推给服务端的下载器 (别名或 ID)
+}
+
+type queryOrder struct {
+	field string
+	desc  bool
+}
+
+// String 返回原始查询语句
+func (q *Query) String() string {
+	return q.src
+}
+
+// ParseQuery 解析查询语句
+func ParseQuery(s string) (*Query, error) {
+	toks, err := lexQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{toks: toks}
+	q := &Query{src: s}
+
+	if !p.atKeyword("order") && !p.atKeyword("limit") && !p.at(tokEOF) {
+		if q.expr, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.atKeyword("order") {
+		p.next()
+		if err := p.expectKeyword("by"); err != nil {
+			return nil, err
+		}
+		for {
+			tok := p.next()
+			name := strings.ToLower(tok.text)
+			f, ok := queryFields[name]
+			if tok.kind != tokIdent || !ok {
+				return nil, &QueryError{tok.pos, fmt.Sprintf("未知的排序字段 %q", tok.text)}
+			}
+			if f.kind == kindTags {
+				return nil, &QueryError{tok.pos, "tags 不支持排序"}
+			}
+			o := queryOrder{field: name}
+			if p.atKeyword("desc") {
+				p.next()
+				o.desc = true
+			} else if p.atKeyword("asc") {
+				p.next()
+			}
+			q.order = append(q.order, o)
+			if !p.at(tokComma) {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.atKeyword("limit") {
+		p.next()
+		tok := p.next()
+		n, err := strconv.Atoi(tok.text)
+		if tok.kind != tokNumber || err != nil || n <= 0 {
+			return nil, &QueryError{tok.pos, "limit 需要正整数"}
+		}
+		q.limit = n
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &QueryError{tok.pos, fmt.Sprintf("多余的内容 %q", tok.text)}
+	}
+
+	q.extractPushdown()
+	return q, nil
+}
+
+// MustParseQuery 解析查询语句，失败时 panic，适用于常量查询
+func MustParseQuery(s string) *Query {
+	q, err := ParseQuery(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Match 判断种子是否满足查询条件 (不考虑排序与 limit)
+func (q *Query) Match(t *Torrent) bool {
+	return q.expr == nil || q.expr.eval(t, time.Now())
+}
+
+// Apply 对种子列表执行过滤、排序与 limit，返回新的切片
+func (q *Query) Apply(torrents []Torrent) []Torrent {
+	now := time.Now()
+	out := make([]Torrent, 0, len(torrents))
+	for i := range torrents {
+		if q.expr == nil || q.expr.eval(&torrents[i], now) {
+			out = append(out, torrents[i])
+		}
+	}
+	if len(q.order) > 0 {
+		sort.SliceStable(out, func(i, j int) bool {
+			for _, o := range q.order {
+				c := compareField(queryFields[o.field], &out[i], &out[j], now)
+				if c == 0 {
+					continue
+				}
+				if o.desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+	if q.limit > 0 && len(out) > q.limit {
+		out = out[:q.limit]
+	}
+	return out
+}
+
+// TorrentListOption 返回可下推给服务端的查询选项：名称关键词与单字段排序。
+// 下载器筛选需要解析别名，由 QueryTorrents 处理。
+func (q *Query) TorrentListOption() TorrentListOption {
+	opt := TorrentListOption{SearchKey: q.searchKey}
+	if len(q.order) == 1 {
+		if f := queryFields[q.order[0].field]; f.sortKey != "" {
+			opt.SortKey = f.sortKey
+			opt.SortType = "asc"
+			if q.order[0].desc != f.reverse {
+				opt.SortType = "desc"
+			}
+		}
+	}
+	return opt
+}
+
+// QueryTorrents 解析并执行查询：名称关键词、排序与下载器筛选交给 Vertex，其余条件在本地过滤
+func (c *Client) QueryTorrents(ctx context.Context, query string) ([]Torrent, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.RunQuery(ctx, q)
+}
+
+// RunQuery 执行已解析的查询
+func (c *Client) RunQuery(ctx context.Context, q *Query) ([]Torrent, error) {
+	opt := q.TorrentListOption()
+	if len(q.clients) > 0 {
+		downloaders, err := c.ListDownloaders(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range downloaders {
+			for _, name := range q.clients {
+				if strings.EqualFold(d.Alias, name) || d.ID == name {
+					opt.ClientList = append(opt.ClientList, d.ID)
+					break
+				}
+			}
+		}
+		if len(opt.ClientList) == 0 {
+			return nil, nil
+		}
+	}
+	torrents, err := c.ListAllTorrents(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	return q.Apply(torrents), nil
+}
+
+// extractPushdown 从顶层 and 条件中提取可交给服务端处理的部分。
+// 被提取的条件仍会在本地再次求值，但本地只能进一步过滤，无法找回服务端漏掉的种子，
+// 因此前提是服务端返回的结果是本地匹配结果的超集：
+//   - 下载器筛选在本地解析为确切的 ID 列表，与本地求值一致；
+//   - 名称关键词假定 Vertex 按不区分大小写的子串匹配种子名称，与 ~ 的本地语义相同。
+//     若服务端的匹配更严格 (如区分大小写)，部分种子会被遗漏。
+func (q *Query) extractPushdown() {
+	var conjuncts []queryNode
+	var walk func(n queryNode)
+	walk = func(n queryNode) {
+		if a, ok := n.(*andNode); ok {
+			walk(a.left)
+			walk(a.right)
+			return
+		}
+		conjuncts = append(conjuncts, n)
+	}
+	if q.expr != nil {
+		walk(q.expr)
+	}
+	for _, n := range conjuncts {
+		cmp, ok := n.(*cmpNode)
+		if !ok {
+			continue
+		}
+		switch {
+		case cmp.field == "name" && cmp.op == "~" && q.searchKey == "":
+			q.searchKey = cmp.values[0].text
+		case cmp.field == "client" && (cmp.op == "=" || cmp.op == "in") && q.clients == nil:
+			for _, v := range cmp.values {
+				q.clients = append(q.clients, v.text)
+			}
+		}
+	}
+}
+
+func compareField(f queryField, a, b *Torrent, now time.Time) int {
+	switch f.kind {
+	case kindString, kindState:
+		return strings.Compare(strings.ToLower(f.str(a)), strings.ToLower(f.str(b)))
+	default:
+		x, y := f.num(a, now), f.num(b, now)
+		switch {
+		case x < y || (math.IsNaN(x) && !math.IsNaN(y)):
+			return -1
+		case x > y || (!math.IsNaN(x) && math.IsNaN(y)):
+			return 1
+		}
+		return 0
+	}
+}
+
+// ------------------------------------------
+// 语法树
+// ------------------------------------------
+
+type queryNode interface {
+	eval(t *Torrent, now time.Time) bool
+}
+
+type andNode struct{ left, right queryNode }
+type orNode struct{ left, right queryNode }
+type notNode struct{ inner queryNode }
+
+func (n *andNode) eval(t *Torrent, now time.Time) bool {
+	return n.left.eval(t, now) && n.right.eval(t, now)
+}
+
+func (n *orNode) eval(t *Torrent, now time.Time) bool {
+	return n.left.eval(t, now) || n.right.eval(t, now)
+}
+
+func (n *notNode) eval(t *Torrent, now time.Time) bool {
+	return !n.inner.eval(t, now)
+}
+
+// queryValue 比较运算右侧的值
+type queryValue struct {
+	text string  // 原始文本
+	num  float64 // 数值 (字节、秒或普通数值)
+}
+
+type cmpNode struct {
+	field  string
+	op     string
+	values []queryValue
+}
+
+// stateGroups state 字段的分组取值
+var stateGroups = map[string]func(TorrentState) bool{
+	"seeding":     TorrentState.IsSeeding,
+	"downloading": TorrentState.IsDownloading,
+	"paused":      TorrentState.IsPaused,
+	"error":       TorrentState.IsError,
+}
+
+func (n *cmpNode) eval(t *Torrent, now time.Time) bool {
+	f := queryFields[n.field]
+	switch f.kind {
+	case kindString, kindState:
+		s := strings.ToLower(f.str(t))
+		match := func(v queryValue) bool {
+			if f.kind == kindState {
+				if group, ok := stateGroups[strings.ToLower(v.text)]; ok {
+					return group(t.State)
+				}
+			}
+			if n.field == "client" && strings.EqualFold(t.ClientID, v.text) {
+				return true
+			}
+			if n.op == "~" || n.op == "!~" {
+				return strings.Contains(s, strings.ToLower(v.text))
+			}
+			return s == strings.ToLower(v.text)
+		}
+		return applyMatch(n.op, n.values, match)
+	case kindTags:
+		return applyMatch(n.op, n.values, func(v queryValue) bool {
+			for _, tag := range t.Tags {
+				tag = strings.ToLower(tag)
+				want := strings.ToLower(v.text)
+				if (n.op == "~" || n.op == "!~") && strings.Contains(tag, want) || tag == want {
+					return true
+				}
+			}
+			return false
+		})
+	default:
+		x := f.num(t, now)
+		if math.IsNaN(x) {
+			return false
+		}
+		switch n.op {
+		case "<":
+			return x < n.values[0].num
+		case "<=":
+			return x <= n.values[0].num
+		case ">":
+			return x > n.values[0].num
+		case ">=":
+			return x >= n.values[0].num
+		}
+		return applyMatch(n.op, n.values, func(v queryValue) bool { return x == v.num })
+	}
+}
+
+// applyMatch 处理 = != ~ !~ in 这类基于匹配的运算
+func applyMatch(op string, values []queryValue, match func(queryValue) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return op != "!=" && op != "!~"
+		}
+	}
+	return op == "!=" || op == "!~"
+}
+
+// ------------------------------------------
+// 语法分析
+// ------------------------------------------
+
+type queryParser struct {
+	toks []queryToken
+	pos  int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.toks[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.toks[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) at(kind tokenKind) bool {
+	return p.peek().kind == kind
+}
+
+func (p *queryParser) atKeyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && strings.EqualFold(tok.text, kw)
+}
+
+func (p *queryParser) expectKeyword(kw string) error {
+	if !p.atKeyword(kw) {
+		tok := p.peek()
+		return &QueryError{tok.pos, fmt.Sprintf("此处需要 %q", kw)}
+	}
+	p.next()
+	return nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.atKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.atKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (queryNode, error) {
+	if p.atKeyword("not") {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{inner}, nil
+	}
+	if p.at(tokLParen) {
+		p.next()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, &QueryError{tok.pos, "缺少右括号"}
+		}
+		return n, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *queryParser) parseCmp() (queryNode, error) {
+	tok := p.next()
+	name := strings.ToLower(tok.text)
+	f, ok := queryFields[name]
+	if tok.kind != tokIdent || !ok {
+		return nil, &QueryError{tok.pos, fmt.Sprintf("未知的字段 %q", tok.text)}
+	}
+
+	n := &cmpNode{field: name}
+	opTok := p.next()
+	switch {
+	case opTok.kind == tokOp:
+		n.op = opTok.text
+		if n.op == "==" {
+			n.op = "="
+		}
+	case opTok.kind == tokIdent && strings.EqualFold(opTok.text, "in"):
+		n.op = "in"
+	default:
+		return nil, &QueryError{opTok.pos, "此处需要比较运算符"}
+	}
+
+	ordered := n.op == "<" || n.op == "<=" || n.op == ">" || n.op == ">="
+	if ordered && (f.kind == kindString || f.kind == kindState || f.kind == kindTags) {
+		return nil, &QueryError{opTok.pos, fmt.Sprintf("字段 %s 不支持运算符 %s", name, n.op)}
+	}
+	if (n.op == "~" || n.op == "!~") && f.kind != kindString && f.kind != kindTags {
+		return nil, &QueryError{opTok.pos, fmt.Sprintf("字段 %s 不支持运算符 %s", name, n.op)}
+	}
+
+	var valueToks []queryToken
+	if n.op == "in" {
+		if tok := p.next(); tok.kind != tokLParen {
+			return nil, &QueryError{tok.pos, "in 之后需要左括号"}
+		}
+		for {
+			valueToks = append(valueToks, p.next())
+			tok := p.next()
+			if tok.kind == tokRParen {
+				break
+			}
+			if tok.kind != tokComma {
+				return nil, &QueryError{tok.pos, "值列表需要以逗号分隔并以右括号结束"}
+			}
+		}
+	} else {
+		valueToks = append(valueToks, p.next())
+	}
+
+	for _, vt := range valueToks {
+		if vt.kind != tokIdent && vt.kind != tokNumber && vt.kind != tokString {
+			return nil, &QueryError{vt.pos, "此处需要值"}
+		}
+		v := queryValue{text: vt.text}
+		var err error
+		switch f.kind {
+		case kindBytes:
+			v.num, err = parseByteSize(vt.text)
+		case kindDuration:
+			v.num, err = parseQueryDuration(vt.text)
+		case kindNumber:
+			v.num, err = strconv.ParseFloat(vt.text, 64)
+		}
+		if err != nil {
+			return nil, &QueryError{vt.pos, fmt.Sprintf("字段 %s 的值 %q 无效", name, vt.text)}
+		}
+		n.values = append(n.values, v)
+	}
+	return n, nil
+}
+
+// byteUnits 字节单位 (小写)
+var byteUnits = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "m": 1e6, "mb": 1e6, "g": 1e9, "gb": 1e9, "t": 1e12, "tb": 1e12,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+}
+
+// durationUnits 时长单位 (秒)
+var durationUnits = map[string]float64{
+	"s": 1, "m": 60, "h": 3600, "d": 86400, "w": 7 * 86400,
+}
+
+var numberWithUnit = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)([a-zA-Z]*)$`)
+
+// parseByteSize 解析带单位的字节数，如 10GiB、1.5GB、1024
+func parseByteSize(s string) (float64, error) {
+	m := numberWithUnit.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("无效的大小: %s", s)
+	}
+	unit, ok := byteUnits[strings.ToLower(m[2])]
+	if !ok {
+		return 0, fmt.Errorf("未知的大小单位: %s", m[2])
+	}
+	n, _ := strconv.ParseFloat(m[1], 64)
+	return n * unit, nil
+}
+
+// parseQueryDuration 解析时长，如 30d、12h，不带单位时按秒计算
+func parseQueryDuration(s string) (float64, error) {
+	m := numberWithUnit.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("无效的时长: %s", s)
+	}
+	unit := 1.0
+	if m[2] != "" {
+		u, ok := durationUnits[strings.ToLower(m[2])]
+		if !ok {
+			return 0, fmt.Errorf("未知的时长单位: %s", m[2])
+		}
+		unit = u
+	}
+	n, _ := strconv.ParseFloat(m[1], 64)
+	return n * unit, nil
+}
+
+// ------------------------------------------
+// 词法分析
+// ------------------------------------------
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type queryToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lexQuery(s string) ([]queryToken, error) {
+	var toks []queryToken
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, queryToken{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, queryToken{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, queryToken{tokComma, ",", i})
+			i++
+		case c == '"' || c == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for i < len(s) && s[i] != c {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			if i >= len(s) {
+				return nil, &QueryError{start, "字符串未结束"}
+			}
+			i++
+			toks = append(toks, queryToken{tokString, b.String(), start})
+		case strings.ContainsRune("=!<>~", rune(c)):
+			start := i
+			op := string(c)
+			if i+1 < len(s) && (s[i+1] == '=' || (c == '!' && s[i+1] == '~')) {
+				op = s[i : i+2]
+			}
+			switch op {
+			case "=", "==", "!=", "<", "<=", ">", ">=", "~", "!~":
+			default:
+				return nil, &QueryError{start, fmt.Sprintf("无效的运算符 %q", op)}
+			}
+			i += len(op)
+			toks = append(toks, queryToken{tokOp, op, start})
+		default:
+			start := i
+			for i < len(s) && isQueryWordByte(s[i]) {
+				i++
+			}
+			if i == start {
+				return nil, &QueryError{start, fmt.Sprintf("无效的字符 %q", c)}
+			}
+			word := s[start:i]
+			kind := tokIdent
+			if word[0] >= '0' && word[0] <= '9' {
+				kind = tokNumber
+			}
+			toks = append(toks, queryToken{kind, word, start})
+		}
+	}
+	return append(toks, queryToken{tokEOF, "", len(s)}), nil
+}
+
+// isQueryWordByte 标识符与不带引号的值可包含的字符 (含 UTF-8 多字节字符)
+func isQueryWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || c == '.' || c == '-' || c == '/' || c == ':' ||
+		unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
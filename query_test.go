@@ -0,0 +1,270 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queryTorrents 查询测试使用的种子，各排序字段的取值互不相同
+func queryTorrents() []Torrent {
+	now := time.Now().Unix()
+	return []Torrent{
+		{Hash: "h1", Name: "Movie.2020.1080p.BluRay", ClientID: "qb1", ClientAlias: "QB-Home", State: StateSeeding, Size: 20 << 30, Ratio: 0.5, UploadSpeed: 100, AddedTime: now - 40*86400, Tags: []string{"keep"}, Tracker: "https://tracker.example.org/announce"},
+		{Hash: "h2", Name: "Show S01E01 720p", ClientID: "qb1", ClientAlias: "QB-Home", State: StateUploading, Size: 2 << 30, Ratio: 3, UploadSpeed: 5000, AddedTime: now - 2*86400, Tracker: "https://other.example.com/announce"},
+		{Hash: "h3", Name: "Album 2019 FLAC", ClientID: "tr1", ClientAlias: "TR", State: StateDownloading, Size: 500 << 20, Ratio: 0.1, UploadSpeed: 10, AddedTime: now - 3600},
+		{Hash: "h4", Name: "movie.2021.1080p.web", ClientID: "tr1", ClientAlias: "TR", State: StatePausedUP, Size: 15 << 30, Ratio: 1.2, UploadSpeed: 0, AddedTime: now - 90*86400, Tags: []string{"Keep-Forever", "hd"}},
+		{Hash: "h5", Name: "Unknown Time", ClientID: "qb1", ClientAlias: "QB-Home", State: StateSeeding, Size: 1, Ratio: 9, UploadSpeed: 1},
+	}
+}
+
+func hashesOf(torrents []Torrent) []string {
+	out := []string{}
+	for _, t := range torrents {
+		out = append(out, t.Hash)
+	}
+	return out
+}
+
+func TestQueryMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// 优先级：not > and > or
+		{"state=downloading or ratio>2 and size>1GiB", []string{"h2", "h3"}},
+		{"(state=downloading or ratio>2) and size>1GiB", []string{"h2"}},
+		{"not state=seeding and size>1GiB", []string{"h4"}},
+		{"not (state=seeding or state=paused)", []string{"h3"}},
+		{"not not state=downloading", []string{"h3"}},
+		// 引号与转义
+		{`name ~ "2020.1080p"`, []string{"h1"}},
+		{`name ~ 'show s01'`, []string{"h2"}},
+		{`name = "album 2019 flac"`, []string{"h3"}},
+		{`name ~ "it\"s"`, []string{}},
+		// 单位后缀：1000 进制与 1024 进制
+		{"size >= 2GiB and size < 16GiB", []string{"h2", "h4"}},
+		{"size > 500MB and size < 1GB", []string{"h3"}},
+		{"size = 500MiB", []string{"h3"}},
+		{"uploadspeed >= 5KB", []string{"h2"}},
+		{"uploadspeed < 1KiB and uploadspeed > 0", []string{"h1", "h3", "h5"}},
+		{"age > 30d", []string{"h1", "h4"}},
+		{"age < 2h", []string{"h3"}},
+		{"age > 1w and age < 8w", []string{"h1"}},
+		// 添加时间未知时任何比较都不成立
+		{"age >= 0", []string{"h1", "h2", "h3", "h4"}},
+		{"not age >= 0", []string{"h5"}},
+		// 其他运算
+		{"client in (qb-home)", []string{"h1", "h2", "h5"}},
+		{"client = tr1", []string{"h3", "h4"}},
+		{"client != QB-HOME", []string{"h3", "h4"}},
+		{"tags = keep", []string{"h1"}},
+		{"tags ~ keep", []string{"h1", "h4"}},
+		{"tags !~ keep", []string{"h2", "h3", "h5"}},
+		{"tracker ~ example.org", []string{"h1"}},
+		{"state = pausedUP", []string{"h4"}},
+		{"state in (seeding, error)", []string{"h1", "h2", "h5"}},
+		{"ratio in (0.5, 3)", []string{"h1", "h2"}},
+		{"", []string{"h1", "h2", "h3", "h4", "h5"}},
+	}
+	torrents := queryTorrents()
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", tt.query, err)
+			continue
+		}
+		got := []string{}
+		for i := range torrents {
+			if q.Match(&torrents[i]) {
+				got = append(got, torrents[i].Hash)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q 匹配 %v, 期望 %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryApplyOrderLimit(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"order by size desc", []string{"h1", "h4", "h2", "h3", "h5"}},
+		{"order by ratio limit 2", []string{"h3", "h1"}},
+		{"state=seeding order by uploadspeed desc", []string{"h2", "h1", "h5"}},
+		{"order by client, size desc", []string{"h1", "h2", "h5", "h4", "h3"}},
+		// 时间未知的种子排在最前 (升序)
+		{"order by age asc limit 3", []string{"h5", "h3", "h2"}},
+	}
+	for _, tt := range tests {
+		got := hashesOf(MustParseQuery(tt.query).Apply(queryTorrents()))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q = %v, 期望 %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"size >", 6},
+		{"size > abc", 7},
+		{"size > 10XB", 7},
+		{"age > 5y", 6},
+		{"unknown = 1", 0},
+		{"name < abc", 5},
+		{"ratio ~ 1", 6},
+		{"tags > 1", 5},
+		{"(state=seeding", 14},
+		{"state=seeding)", 13},
+		{`name = "abc`, 7},
+		{"client in qb", 10},
+		{"client in (a b)", 13},
+		{"client in (a,", 13},
+		{"state seeding", 6},
+		{"size => 1", 6},
+		{"size >< 1", 6},
+		{"a & b", 2},
+		{"state=seeding and", 17},
+		{"state=seeding extra", 14},
+		{"order size", 6},
+		{"order by nothing", 9},
+		{"order by tags", 9},
+		{"limit 0", 6},
+		{"limit -1", 6},
+		{"limit ten", 6},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query)
+		var qe *QueryError
+		if !errors.As(err, &qe) {
+			t.Errorf("ParseQuery(%q) 错误 = %v, 期望 QueryError", tt.query, err)
+			continue
+		}
+		if qe.Pos != tt.pos {
+			t.Errorf("ParseQuery(%q) 错误位置 = %d (%s), 期望 %d", tt.query, qe.Pos, qe.Msg, tt.pos)
+		}
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	sizes := map[string]float64{
+		"1024": 1024, "1b": 1, "1KB": 1000, "1k": 1000, "1KiB": 1024,
+		"1.5GB": 1.5e9, "10GiB": 10 << 30, "2tib": 2 << 40,
+	}
+	for in, want := range sizes {
+		if got, err := parseByteSize(in); err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %v, %v, 期望 %v", in, got, err, want)
+		}
+	}
+	durations := map[string]float64{
+		"90": 90, "30s": 30, "5m": 300, "1.5h": 5400, "30d": 30 * 86400, "2W": 14 * 86400,
+	}
+	for in, want := range durations {
+		if got, err := parseQueryDuration(in); err != nil || got != want {
+			t.Errorf("parseQueryDuration(%q) = %v, %v, 期望 %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "GB", "1.GB", "-1GB", "1XB", "1 GB"} {
+		if _, err := parseByteSize(in); err == nil {
+			t.Errorf("parseByteSize(%q) 期望返回错误", in)
+		}
+	}
+	for _, in := range []string{"", "d", "5y", "1.5.5d"} {
+		if _, err := parseQueryDuration(in); err == nil {
+			t.Errorf("parseQueryDuration(%q) 期望返回错误", in)
+		}
+	}
+	if !math.IsNaN(sinceSeconds(0, time.Now())) {
+		t.Error("未知时间应返回 NaN")
+	}
+}
+
+func TestQueryPushdown(t *testing.T) {
+	tests := []struct {
+		query     string
+		searchKey string
+		clients   []string
+		sortKey   string
+		sortType  string
+	}{
+		{"name ~ 1080p and size > 1GB", "1080p", nil, "", ""},
+		{"name ~ 1080p or size > 1GB", "", nil, "", ""},
+		{"client in (qb1, TR) and name ~ x order by age", "x", []string{"qb1", "TR"}, "addedTime", "desc"},
+		{"not client = qb1 order by size desc", "", nil, "size", "desc"},
+		{"order by size, ratio", "", nil, "", ""},
+		{"order by tracker", "", nil, "tracker", "asc"},
+		{"order by seeders", "", nil, "", ""},
+	}
+	for _, tt := range tests {
+		q := MustParseQuery(tt.query)
+		opt := q.TorrentListOption()
+		if q.searchKey != tt.searchKey || !reflect.DeepEqual(q.clients, tt.clients) || opt.SortKey != tt.sortKey || opt.SortType != tt.sortType {
+			t.Errorf("%q 下推 = %q %v %s %s, 期望 %q %v %s %s", tt.query, q.searchKey, q.clients, opt.SortKey, opt.SortType,
+				tt.searchKey, tt.clients, tt.sortKey, tt.sortType)
+		}
+	}
+}
+
+// TestRunQueryMatchesLocal 服务端下推 (关键词、下载器、排序) 加本地过滤的结果应与只在本地求值一致
+func TestRunQueryMatchesLocal(t *testing.T) {
+	qb, tr := DownloaderInfo{}, DownloaderInfo{}
+	qb.ID, qb.Alias = "qb1", "QB-Home"
+	tr.ID, tr.Alias = "tr1", "TR"
+	torrents := queryTorrents()
+
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/downloader/list": staticRoute([]DownloaderInfo{qb, tr}),
+		// 模拟服务端：按下载器与关键词过滤，并以与本地不同的顺序返回
+		"/api/torrent/list": func(r *http.Request) (interface{}, error) {
+			var clients []string
+			_ = json.Unmarshal([]byte(r.URL.Query().Get("clientList")), &clients)
+			key := strings.ToLower(r.URL.Query().Get("searchKey"))
+			var matched []Torrent
+			for i := len(torrents) - 1; i >= 0; i-- {
+				t := torrents[i]
+				if containsString(clients, t.ClientID) && strings.Contains(strings.ToLower(t.Name), key) {
+					matched = append(matched, t)
+				}
+			}
+			return TorrentListResult{Torrents: matched, Total: len(matched)}, nil
+		},
+	})
+
+	for _, query := range []string{
+		"name ~ 1080p",
+		"name ~ MOVIE and size > 16GB",
+		"client = qb-home and state = seeding order by ratio desc",
+		"client in (tr1, nobody) and not name ~ album",
+		"client = nobody",
+		"name ~ 1080p or client = tr",
+		"ratio < 2 order by age desc limit 2",
+		"tags ~ keep order by size",
+	} {
+		q := MustParseQuery(query)
+		got, err := c.RunQuery(context.Background(), q)
+		if err != nil {
+			t.Fatalf("%q: %v", query, err)
+		}
+		gotHashes, wantHashes := hashesOf(got), hashesOf(q.Apply(torrents))
+		if len(q.order) == 0 {
+			// 未指定排序时结果顺序取决于服务端，只比较集合
+			sort.Strings(gotHashes)
+			sort.Strings(wantHashes)
+		}
+		if !reflect.DeepEqual(gotHashes, wantHashes) {
+			t.Errorf("%q 下推结果 %v, 本地求值 %v", query, gotHashes, wantHashes)
+		}
+	}
+}
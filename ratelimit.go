@@ -0,0 +1,168 @@
+package vertex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// 客户端限流与并发控制 (Rate Limit)
+// ==========================================
+
+// WithRateLimit 配置全局请求速率限制：每秒 rps 个请求，允许 burst 个突发请求。
+// 超出速率的请求会等待，等待过程受 ctx 控制。
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) error {
+		c.limits.global.limiter = newRateLimiter(rps, burst)
+		return nil
+	}
+}
+
+// WithMaxConcurrency 配置全局最大并发请求数
+func WithMaxConcurrency(n int) ClientOption {
+	return func(c *Client) error {
+		c.limits.global.sem = newSemaphore(n)
+		return nil
+	}
+}
+
+// WithEndpointRateLimit 为指定接口 (如 "/api/torrent/list") 单独配置速率限制，
+// 该接口的请求不再占用全局速率配额
+func WithEndpointRateLimit(path string, rps float64, burst int) ClientOption {
+	return func(c *Client) error {
+		c.limits.endpoint(path).limiter = newRateLimiter(rps, burst)
+		return nil
+	}
+}
+
+// WithEndpointMaxConcurrency 为指定接口单独配置最大并发数，该接口的请求不再占用全局并发配额
+func WithEndpointMaxConcurrency(path string, n int) ClientOption {
+	return func(c *Client) error {
+		c.limits.endpoint(path).sem = newSemaphore(n)
+		return nil
+	}
+}
+
+// throttle 限流规则集合
+type throttle struct {
+	global    limitRule
+	endpoints map[string]*limitRule
+}
+
+// limitRule 一组速率与并发限制，nil 字段表示不限制
+type limitRule struct {
+	limiter *rateLimiter
+	sem     semaphore
+}
+
+func (t *throttle) endpoint(path string) *limitRule {
+	if t.endpoints == nil {
+		t.endpoints = make(map[string]*limitRule)
+	}
+	r, ok := t.endpoints[path]
+	if !ok {
+		r = &limitRule{}
+		t.endpoints[path] = r
+	}
+	return r
+}
+
+// acquire 等待请求许可，返回的 release 必须在请求结束后调用
+func (t *throttle) acquire(ctx context.Context, path string) (release func(), err error) {
+	limiter, sem := t.global.limiter, t.global.sem
+	if r, ok := t.endpoints[path]; ok {
+		if r.limiter != nil {
+			limiter = r.limiter
+		}
+		if r.sem != nil {
+			sem = r.sem
+		}
+	}
+
+	if limiter != nil {
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if sem != nil {
+		if err := sem.acquire(ctx); err != nil {
+			return nil, err
+		}
+		return sem.release, nil
+	}
+	return func() {}, nil
+}
+
+// rateLimiter 令牌桶限速器
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  float64 // 桶容量
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait 预定一个令牌，令牌不足时等待补充；ctx 结束时归还预定的令牌
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// semaphore 基于带缓冲通道的信号量
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n < 1 {
+		n = 1
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	<-s
+}
@@ -0,0 +1,249 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newThrottle 应用限流选项并返回得到的规则集合
+func newThrottle(t *testing.T, opts ...ClientOption) *throttle {
+	t.Helper()
+	c := &Client{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &c.limits
+}
+
+// mustAcquire 获取许可，超过 50ms 未获取到时失败
+func mustAcquire(t *testing.T, th *throttle, path string) func() {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	release, err := th.acquire(ctx, path)
+	if err != nil {
+		t.Fatalf("%s 应能立即获取许可: %v", path, err)
+	}
+	return release
+}
+
+// blocked 判断 path 在 20ms 内是否无法获取许可
+func blocked(th *throttle, path string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	release, err := th.acquire(ctx, path)
+	if err == nil {
+		release()
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := newRateLimiter(50, 3) // 每 20ms 补充一个令牌
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d > 10*time.Millisecond {
+		t.Fatalf("突发额度内的请求不应等待，实际耗时 %v", d)
+	}
+
+	start = time.Now()
+	if err := l.wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Fatalf("令牌耗尽后应等待补充，实际耗时 %v", d)
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	l := newRateLimiter(100, 2)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_ = l.wait(ctx)
+	}
+	time.Sleep(30 * time.Millisecond) // 补充约 3 个令牌，但不超过桶容量 2
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d > 10*time.Millisecond {
+		t.Fatalf("补充后的令牌应可立即使用，实际耗时 %v", d)
+	}
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens > 0.5 {
+		t.Fatalf("令牌数不应超过桶容量，剩余 %v", tokens)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	for _, rps := range []float64{0, -1} {
+		l := newRateLimiter(rps, 1)
+		for i := 0; i < 100; i++ {
+			if err := l.wait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := newRateLimiter(10, 1) // 每 100ms 补充一个令牌
+	_ = l.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("期望超时错误，实际: %v", err)
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Fatalf("ctx 结束后应立即返回，实际耗时 %v", d)
+	}
+	// 取消的等待归还预定的令牌，不会推迟后续请求
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.5 {
+		t.Fatalf("取消后应归还令牌，剩余 %v", tokens)
+	}
+}
+
+func TestSemaphore(t *testing.T) {
+	s := newSemaphore(2)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := s.acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := s.acquire(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("并发已满时应等待到超时，实际: %v", err)
+	}
+
+	s.release()
+	if err := s.acquire(ctx); err != nil {
+		t.Fatalf("释放后应能获取: %v", err)
+	}
+
+	if cap(newSemaphore(0)) != 1 {
+		t.Fatal("并发数小于 1 时应按 1 处理")
+	}
+}
+
+func TestThrottleEndpointOverride(t *testing.T) {
+	th := newThrottle(t,
+		WithMaxConcurrency(1),
+		WithEndpointMaxConcurrency("/api/torrent/list", 1),
+	)
+	release := mustAcquire(t, th, "/api/server/list")
+	// 单独配置的接口不占用全局并发配额
+	releaseList := mustAcquire(t, th, "/api/torrent/list")
+	if !blocked(th, "/api/downloader/list") {
+		t.Fatal("全局并发已满时其他接口应等待")
+	}
+	if !blocked(th, "/api/torrent/list") {
+		t.Fatal("接口自身的并发配额已满时应等待")
+	}
+	release()
+	releaseList()
+	mustAcquire(t, th, "/api/downloader/list")()
+
+	th = newThrottle(t,
+		WithRateLimit(0.001, 1),
+		WithEndpointRateLimit("/api/torrent/list", 1000, 5),
+	)
+	mustAcquire(t, th, "/api/server/list")()
+	if !blocked(th, "/api/downloader/list") {
+		t.Fatal("全局速率配额耗尽时其他接口应等待")
+	}
+	// 单独配置速率的接口使用自己的令牌桶
+	for i := 0; i < 5; i++ {
+		mustAcquire(t, th, "/api/torrent/list")()
+	}
+}
+
+func TestThrottleUnlimited(t *testing.T) {
+	th := newThrottle(t)
+	for i := 0; i < 10; i++ {
+		mustAcquire(t, th, "/api/server/list")
+	}
+}
+
+func TestClientMaxConcurrency(t *testing.T) {
+	var inFlight, peak int32
+	c, srv := newTestClient(t, map[string]testRoute{
+		"/api/server/list": func(*http.Request) (interface{}, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return []Server{}, nil
+		},
+	}, WithMaxConcurrency(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.ListServers(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if peak := atomic.LoadInt32(&peak); peak != 2 {
+		t.Fatalf("最大并发应为 2，实际 %d", peak)
+	}
+	if n := srv.Calls("/api/server/list"); n != 6 {
+		t.Fatalf("期望请求 6 次，实际 %d 次", n)
+	}
+}
+
+func TestClientThrottleCancel(t *testing.T) {
+	started, gate := make(chan struct{}, 1), make(chan struct{})
+	c, srv := newTestClient(t, map[string]testRoute{
+		"/api/server/list": gatedRoute(started, gate, []Server{}),
+	}, WithMaxConcurrency(1))
+	defer close(gate)
+
+	go func() { _, _ = c.ListServers(context.Background()) }()
+	<-started
+
+	// 等待许可期间 ctx 超时，请求不会发出
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListServers(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("期望超时错误，实际: %v", err)
+	}
+	if n := srv.Calls("/api/server/list"); n != 1 {
+		t.Fatalf("等待许可超时的请求不应发出，实际请求 %d 次", n)
+	}
+}
@@ -0,0 +1,145 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// 重试策略 (Retry Policy)
+// ==========================================
+
+// RetryPolicy 请求重试策略。
+// 以 add/delete 开头的接口 (如 /api/downloader/add、/api/torrent/deleteTorrent) 重试可能造成重复操作，
+// 即使其方法在 Methods 中也不会重试，除非显式列在 Endpoints 中。
+type RetryPolicy struct {
+	MaxRetries     int           // 最大重试次数 (不含首次请求)，0 表示不重试
+	WaitMin        time.Duration // 首次重试的基础等待时间
+	WaitMax        time.Duration // 指数退避的等待上限
+	MaxRetryAfter  time.Duration // 服务端 Retry-After 允许的最长等待，超过则直接放弃 (0 表示不限制)
+	Methods        []string      // 允许重试的 HTTP 方法
+	Endpoints      []string      // 无论方法与幂等性都允许重试的接口 (显式选择)
+	OnNetworkError bool          // 网络错误时重试
+	OnServerError  bool          // HTTP 5xx 时重试
+	OnTooManyReqs  bool          // HTTP 429 时重试
+}
+
+// DefaultRetryPolicy 返回默认重试策略：GET 请求在网络错误、5xx、429 时最多重试 3 次，等待 200ms~3s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		WaitMin:        200 * time.Millisecond,
+		WaitMax:        3 * time.Second,
+		MaxRetryAfter:  30 * time.Second,
+		Methods:        []string{http.MethodGet},
+		OnNetworkError: true,
+		OnServerError:  true,
+		OnTooManyReqs:  true,
+	}
+}
+
+// WithRetryPolicy 配置请求重试策略
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.retry = p
+		return nil
+	}
+}
+
+// WithoutRetry 关闭所有重试
+func WithoutRetry() ClientOption {
+	return func(c *Client) error {
+		c.retry = RetryPolicy{}
+		return nil
+	}
+}
+
+// allows 判断该接口是否允许重试
+func (p *RetryPolicy) allows(method, path string) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	for _, e := range p.Endpoints {
+		if e == path {
+			return true
+		}
+	}
+	if !isIdempotentEndpoint(path) {
+		return false
+	}
+	for _, m := range p.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryable 判断该错误是否满足重试条件
+func (p *RetryPolicy) retryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusTooManyRequests:
+			return p.OnTooManyReqs
+		case httpErr.StatusCode >= 500:
+			return p.OnServerError
+		}
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return p.OnNetworkError
+}
+
+// wait 计算第 attempt 次重试 (从 0 开始) 前的等待时间，返回 false 表示不应再重试
+func (p *RetryPolicy) wait(attempt int, err error) (time.Duration, bool) {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		if p.MaxRetryAfter > 0 && httpErr.RetryAfter > p.MaxRetryAfter {
+			return 0, false
+		}
+		return httpErr.RetryAfter, true
+	}
+
+	// 指数退避 + 抖动：在 [base/2, base) 之间随机
+	base := p.WaitMin
+	for i := 0; i < attempt && base < p.WaitMax; i++ {
+		base *= 2
+	}
+	if p.WaitMax > 0 && base > p.WaitMax {
+		base = p.WaitMax
+	}
+	if base <= 0 {
+		return 0, true
+	}
+	half := base / 2
+	return half + time.Duration(rand.Int63n(int64(base-half)+1)), true
+}
+
+// isIdempotentEndpoint 以 add/delete 开头的接口会新建或删除对象，默认视为非幂等
+func isIdempotentEndpoint(path string) bool {
+	name := path[strings.LastIndex(path, "/")+1:]
+	return !strings.HasPrefix(name, "add") && !strings.HasPrefix(name, "delete")
+}
+
+// parseRetryAfter 解析 Retry-After 头 (秒数或 HTTP 日期)
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryCancelDuringBackoff(t *testing.T) {
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// 首次请求失败后稍后取消，使等待重试的过程被中断
+			time.AfterFunc(50*time.Millisecond, cancel)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.WaitMin, policy.WaitMax = time.Hour, time.Hour
+	c, err := NewClient(context.Background(), srv.URL, WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.ListServers(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, 期望包含 context.Canceled", err)
+	}
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, 期望保留上次的 HTTP 503 错误", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("请求了 %d 次, 期望 1 次", n)
+	}
+}
+
+func TestRetryAllows(t *testing.T) {
+	p := DefaultRetryPolicy()
+	p.Endpoints = []string{"/api/torrent/addTorrent"}
+	tests := []struct {
+		method, path string
+		want         bool
+	}{
+		{"GET", "/api/server/list", true},
+		{"POST", "/api/server/modify", false},
+		{"GET", "/api/torrent/deleteTorrent", false},
+		{"POST", "/api/torrent/addTorrent", true},
+	}
+	for _, tt := range tests {
+		if got := p.allows(tt.method, tt.path); got != tt.want {
+			t.Errorf("allows(%s %s) = %v, 期望 %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package vertex
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ==========================================
+// 跨下载器/跨实例种子检索 (Search)
+// ==========================================
+
+// TorrentCopy 种子在某个下载器上的一份副本
+type TorrentCopy struct {
+	Instance     string  // 所属实例名称 (单实例检索时为空)
+	DownloaderID string  // 所属下载器 ID
+	Torrent      Torrent // 种子信息
+}
+
+// TorrentGroup 一组被视为同一内容的种子副本
+type TorrentGroup struct {
+	Key       string        // 分组键：Hash，或 "规范化名称|大小"
+	Name      string        // 种子名 (取第一份副本)
+	Size      int64         // 种子大小
+	Hashes    []string      // 组内出现的所有 Hash (已排序)
+	Copies    []TorrentCopy // 所有副本
+	Locations int           // 实际占用存储的份数：同服务器下载器 (SameServerClients) 中同一 Hash 的副本共用数据，只计一份
+}
+
+// WastedBytes 除保留一份外，其余副本占用的空间 (同服务器下载器共用的数据不计入)
+func (g *TorrentGroup) WastedBytes() int64 {
+	if g.Locations < 2 {
+		return 0
+	}
+	return g.Size * int64(g.Locations-1)
+}
+
+// MissingCopy 种子存在于某个下载器，但在其同服务器下载器 (SameServerClients) 中缺失
+type MissingCopy struct {
+	Instance     string  // 所属实例名称
+	Torrent      Torrent // 已存在的副本
+	DownloaderID string  // 已存在副本所在的下载器
+	MissingIn    string  // 缺失该种子的同服务器下载器 ID
+}
+
+// TorrentSearchOption 种子检索选项
+type TorrentSearchOption struct {
+	ClientList []string // 限定下载器 ID 列表，为空表示所有下载器；Pool 检索时使用 "实例/ID" 格式
+	SearchKey  string   // 关键词 (交给 Vertex 服务端过滤)
+	Hashes     []string // 只保留指定 Hash 的种子
+}
+
+// TorrentSearchResult 种子检索结果
+type TorrentSearchResult struct {
+	ByHash     []TorrentGroup // 按 Hash 分组
+	ByNameSize []TorrentGroup // 按规范化名称与大小分组
+	Duplicates []TorrentGroup // 浪费空间的重复种子：同一 Hash 存在多份，或同名同大小但 Hash 不同
+	Missing    []MissingCopy  // 在同服务器下载器中缺失的种子
+}
+
+// SearchTorrents 在当前实例的所有下载器中检索种子，并按 Hash、名称/大小分组
+func (c *Client) SearchTorrents(ctx context.Context, opt TorrentSearchOption) (*TorrentSearchResult, error) {
+	copies, downloaders, err := c.collectCopies(ctx, "", opt)
+	if err != nil {
+		return nil, err
+	}
+	return analyzeCopies(copies, downloaders), nil
+}
+
+// SearchTorrents 在池中所有实例的所有下载器中检索种子，失败实例的错误单独返回
+func (p *Pool) SearchTorrents(ctx context.Context, opt TorrentSearchOption) (*TorrentSearchResult, map[string]error) {
+	type instanceCopies struct {
+		copies      []TorrentCopy
+		downloaders map[string]DownloaderInfo
+	}
+	res := PoolCollect(ctx, p, func(ctx context.Context, name string, c *Client) (instanceCopies, error) {
+		copies, downloaders, err := c.collectCopies(ctx, name, opt)
+		return instanceCopies{copies, downloaders}, err
+	})
+
+	var all []TorrentCopy
+	downloaders := make(map[string]DownloaderInfo)
+	for _, v := range res.Values {
+		all = append(all, v.copies...)
+		for key, d := range v.downloaders {
+			downloaders[key] = d
+		}
+	}
+	return analyzeCopies(all, downloaders), res.Errors
+}
+
+// collectCopies 拉取种子并关联到下载器，返回的下载器映射以 "实例/ID" 为键。
+// instance 不为空时 (Pool 检索)，opt.ClientList 中的下载器需写成 "实例/ID"。
+func (c *Client) collectCopies(ctx context.Context, instance string, opt TorrentSearchOption) ([]TorrentCopy, map[string]DownloaderInfo, error) {
+	list, err := c.ListDownloaders(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wanted := make(map[string]bool, len(opt.ClientList))
+	for _, id := range opt.ClientList {
+		wanted[id] = true
+	}
+	downloaders := make(map[string]DownloaderInfo)
+	idByAlias := make(map[string]string)
+	var ids []string
+	for _, d := range list {
+		key := d.ID
+		if instance != "" {
+			key = instance + "/" + d.ID
+		}
+		if len(wanted) > 0 && !wanted[key] {
+			continue
+		}
+		downloaders[instance+"/"+d.ID] = d
+		idByAlias[d.Alias] = d.ID
+		ids = append(ids, d.ID)
+	}
+	if len(ids) == 0 {
+		return nil, downloaders, nil
+	}
+
+	torrents, err := c.ListAllTorrents(ctx, TorrentListOption{ClientList: ids, SearchKey: opt.SearchKey})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make(map[string]bool, len(opt.Hashes))
+	for _, h := range opt.Hashes {
+		hashes[strings.ToLower(h)] = true
+	}
+	copies := make([]TorrentCopy, 0, len(torrents))
+	for _, t := range torrents {
+		if len(hashes) > 0 && !hashes[strings.ToLower(t.Hash)] {
+			continue
+		}
+		id := t.ClientID
+		if id == "" {
+			id = idByAlias[t.ClientAlias]
+		}
+		copies = append(copies, TorrentCopy{Instance: instance, DownloaderID: id, Torrent: t})
+	}
+	return copies, downloaders, nil
+}
+
+// analyzeCopies 对副本分组并找出重复与缺失
+func analyzeCopies(copies []TorrentCopy, downloaders map[string]DownloaderInfo) *TorrentSearchResult {
+	byHash := groupCopies(copies, func(tc TorrentCopy) string { return strings.ToLower(tc.Torrent.Hash) })
+	byNameSize := groupCopies(copies, func(tc TorrentCopy) string {
+		return fmt.Sprintf("%s|%d", NormalizeTorrentName(tc.Torrent.Name), tc.Torrent.Size)
+	})
+
+	for _, groups := range [][]TorrentGroup{byHash, byNameSize} {
+		for i := range groups {
+			groups[i].Locations = countLocations(groups[i].Copies, downloaders)
+		}
+	}
+
+	res := &TorrentSearchResult{ByHash: byHash, ByNameSize: byNameSize}
+	for _, g := range byHash {
+		if g.Locations > 1 {
+			res.Duplicates = append(res.Duplicates, g)
+		}
+	}
+	for _, g := range byNameSize {
+		if len(g.Hashes) > 1 {
+			res.Duplicates = append(res.Duplicates, g)
+		}
+	}
+
+	// 检查同服务器下载器中是否缺失同一 Hash 的种子
+	present := make(map[string]bool, len(copies))
+	for _, tc := range copies {
+		present[tc.Instance+"/"+tc.DownloaderID+"/"+strings.ToLower(tc.Torrent.Hash)] = true
+	}
+	for _, tc := range copies {
+		d, ok := downloaders[tc.Instance+"/"+tc.DownloaderID]
+		if !ok {
+			continue
+		}
+		for _, peer := range d.SameServerClients {
+			if peer == tc.DownloaderID {
+				continue
+			}
+			// 只检查本次检索范围内的下载器
+			if _, ok := downloaders[tc.Instance+"/"+peer]; !ok {
+				continue
+			}
+			if !present[tc.Instance+"/"+peer+"/"+strings.ToLower(tc.Torrent.Hash)] {
+				res.Missing = append(res.Missing, MissingCopy{
+					Instance:     tc.Instance,
+					Torrent:      tc.Torrent,
+					DownloaderID: tc.DownloaderID,
+					MissingIn:    peer,
+				})
+			}
+		}
+	}
+	return res
+}
+
+// countLocations 统计副本实际占用存储的份数：同一实例中，同一 Hash 位于互为同服务器下载器上的副本合并为一份
+func countLocations(copies []TorrentCopy, downloaders map[string]DownloaderInfo) int {
+	// 并查集，parent[i] 为副本 i 所在集合的代表
+	parent := make([]int, len(copies))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range copies {
+		for j := i + 1; j < len(copies); j++ {
+			if sameStorage(copies[i], copies[j], downloaders) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+	n := 0
+	for i := range parent {
+		if find(i) == i {
+			n++
+		}
+	}
+	return n
+}
+
+// sameStorage 判断两份副本是否为同一服务器上共用数据的同一种子
+func sameStorage(a, b TorrentCopy, downloaders map[string]DownloaderInfo) bool {
+	if a.Instance != b.Instance || !strings.EqualFold(a.Torrent.Hash, b.Torrent.Hash) {
+		return false
+	}
+	da := downloaders[a.Instance+"/"+a.DownloaderID]
+	db := downloaders[b.Instance+"/"+b.DownloaderID]
+	return containsString(da.SameServerClients, b.DownloaderID) || containsString(db.SameServerClients, a.DownloaderID)
+}
+
+// groupCopies 按 key 分组，结果按分组键排序
+func groupCopies(copies []TorrentCopy, key func(TorrentCopy) string) []TorrentGroup {
+	index := make(map[string]int)
+	var groups []TorrentGroup
+	for _, tc := range copies {
+		k := key(tc)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, TorrentGroup{Key: k, Name: tc.Torrent.Name, Size: tc.Torrent.Size})
+		}
+		g := &groups[i]
+		g.Copies = append(g.Copies, tc)
+		hash := strings.ToLower(tc.Torrent.Hash)
+		if !containsString(g.Hashes, hash) {
+			g.Hashes = append(g.Hashes, hash)
+			sort.Strings(g.Hashes)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups
+}
+
+// nameSeparators 种子名中常见的分隔符
+var nameSeparators = regexp.MustCompile(`[\s._\-\[\]()]+`)
+
+// NormalizeTorrentName 规范化种子名：转小写，并将空格、点、下划线、括号等分隔符统一为单个空格
+func NormalizeTorrentName(name string) string {
+	return strings.TrimSpace(nameSeparators.ReplaceAllString(strings.ToLower(name), " "))
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package vertex
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTorrentName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Avatar.2009.1080p.BluRay", "avatar 2009 1080p bluray"},
+		{"Avatar 2009 1080p BluRay", "avatar 2009 1080p bluray"},
+		{"[Group] Show_S01E02 (1080p)", "group show s01e02 1080p"},
+		{"  Movie--Name..mkv  ", "movie name mkv"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTorrentName(tt.in); got != tt.want {
+			t.Errorf("NormalizeTorrentName(%q) = %q, 期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func copyOf(instance, downloader, hash, name string, size int64) TorrentCopy {
+	return TorrentCopy{Instance: instance, DownloaderID: downloader, Torrent: Torrent{Hash: hash, Name: name, Size: size}}
+}
+
+func TestGroupCopies(t *testing.T) {
+	copies := []TorrentCopy{
+		copyOf("", "qb", "BBB", "Movie.2020", 100),
+		copyOf("", "tr", "aaa", "Movie 2020", 100),
+		copyOf("", "tr", "bbb", "Movie.2020", 100),
+	}
+	byHash := groupCopies(copies, func(tc TorrentCopy) string { return tc.Torrent.Hash })
+	if len(byHash) != 3 {
+		t.Fatalf("区分大小写的分组数 = %d, 期望 3", len(byHash))
+	}
+
+	tests := []struct {
+		name       string
+		key        func(TorrentCopy) string
+		wantKeys   []string
+		wantHashes [][]string
+		wantCopies []int
+	}{
+		{
+			name:       "按 Hash (忽略大小写)",
+			key:        func(tc TorrentCopy) string { return strings.ToLower(tc.Torrent.Hash) },
+			wantKeys:   []string{"aaa", "bbb"},
+			wantHashes: [][]string{{"aaa"}, {"bbb"}},
+			wantCopies: []int{1, 2},
+		},
+		{
+			name:       "按规范化名称",
+			key:        func(tc TorrentCopy) string { return NormalizeTorrentName(tc.Torrent.Name) },
+			wantKeys:   []string{"movie 2020"},
+			wantHashes: [][]string{{"aaa", "bbb"}},
+			wantCopies: []int{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := groupCopies(copies, tt.key)
+			var keys []string
+			var hashes [][]string
+			var counts []int
+			for _, g := range groups {
+				keys = append(keys, g.Key)
+				hashes = append(hashes, g.Hashes)
+				counts = append(counts, len(g.Copies))
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) || !reflect.DeepEqual(hashes, tt.wantHashes) || !reflect.DeepEqual(counts, tt.wantCopies) {
+				t.Errorf("分组 = %v %v %v, 期望 %v %v %v", keys, hashes, counts, tt.wantKeys, tt.wantHashes, tt.wantCopies)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCopies(t *testing.T) {
+	downloader := func(id string, sameServer ...string) DownloaderInfo {
+		var d DownloaderInfo
+		d.ID = id
+		d.SameServerClients = sameServer
+		return d
+	}
+	// qb1 与 tr1 位于同一服务器共用数据，qb2 位于另一台服务器
+	downloaders := map[string]DownloaderInfo{
+		"/qb1": downloader("qb1", "tr1"),
+		"/tr1": downloader("tr1"),
+		"/qb2": downloader("qb2"),
+	}
+
+	tests := []struct {
+		name          string
+		copies        []TorrentCopy
+		wantDupKeys   []string
+		wantWasted    []int64
+		wantMissingIn []string
+	}{
+		{
+			name:   "同服务器的同一 Hash 不算重复",
+			copies: []TorrentCopy{copyOf("", "qb1", "aaa", "A", 100), copyOf("", "tr1", "aaa", "A", 100)},
+		},
+		{
+			name:          "跨服务器的同一 Hash 算重复",
+			copies:        []TorrentCopy{copyOf("", "qb1", "aaa", "A", 100), copyOf("", "qb2", "aaa", "A", 100)},
+			wantDupKeys:   []string{"aaa"},
+			wantWasted:    []int64{100},
+			wantMissingIn: []string{"tr1"},
+		},
+		{
+			name: "同服务器与跨服务器混合时只计跨服务器的份数",
+			copies: []TorrentCopy{
+				copyOf("", "qb1", "aaa", "A", 100),
+				copyOf("", "tr1", "aaa", "A", 100),
+				copyOf("", "qb2", "aaa", "A", 100),
+			},
+			wantDupKeys: []string{"aaa"},
+			wantWasted:  []int64{100},
+		},
+		{
+			name:          "同名同大小但 Hash 不同",
+			copies:        []TorrentCopy{copyOf("", "qb1", "aaa", "Movie.2020", 100), copyOf("", "qb2", "bbb", "Movie 2020", 100)},
+			wantDupKeys:   []string{"movie 2020|100"},
+			wantWasted:    []int64{100},
+			wantMissingIn: []string{"tr1"},
+		},
+		{
+			name:          "同服务器下载器缺失",
+			copies:        []TorrentCopy{copyOf("", "tr1", "aaa", "A", 100)},
+			wantMissingIn: nil, // tr1 未把 qb1 列为同服务器下载器
+		},
+		{
+			name:          "反向缺失",
+			copies:        []TorrentCopy{copyOf("", "qb1", "aaa", "A", 100)},
+			wantMissingIn: []string{"tr1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := analyzeCopies(tt.copies, downloaders)
+			var keys []string
+			var wasted []int64
+			for _, g := range res.Duplicates {
+				keys = append(keys, g.Key)
+				wasted = append(wasted, g.WastedBytes())
+			}
+			var missing []string
+			for _, m := range res.Missing {
+				missing = append(missing, m.MissingIn)
+			}
+			sort.Strings(missing)
+			if !reflect.DeepEqual(keys, tt.wantDupKeys) || !reflect.DeepEqual(wasted, tt.wantWasted) {
+				t.Errorf("重复 = %v 浪费 %v, 期望 %v %v", keys, wasted, tt.wantDupKeys, tt.wantWasted)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissingIn) {
+				t.Errorf("缺失 = %v, 期望 %v", missing, tt.wantMissingIn)
+			}
+		})
+	}
+}
+
+func TestCollectCopiesInstanceClientList(t *testing.T) {
+	qb, tr := DownloaderInfo{}, DownloaderInfo{}
+	qb.ID, qb.Alias = "qb", "qb"
+	tr.ID, tr.Alias = "tr", "tr"
+	torrents := []Torrent{{Hash: "a", ClientID: "qb"}, {Hash: "b", ClientID: "tr"}}
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/downloader/list": staticRoute([]DownloaderInfo{qb, tr}),
+		"/api/torrent/list":    torrentListRoute(func() []Torrent { return torrents }),
+	})
+
+	tests := []struct {
+		instance   string
+		clientList []string
+		want       []string
+	}{
+		{"", []string{"qb"}, []string{"/qb"}},
+		{"hk", []string{"hk/qb"}, []string{"hk/qb"}},
+		// Pool 检索时未带实例名的 ID 不匹配任何下载器
+		{"hk", []string{"qb"}, nil},
+		{"hk", []string{"us/qb"}, nil},
+		{"hk", nil, []string{"hk/qb", "hk/tr"}},
+	}
+	for _, tt := range tests {
+		copies, _, err := c.collectCopies(context.Background(), tt.instance, TorrentSearchOption{ClientList: tt.clientList})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, tc := range copies {
+			got = append(got, tc.Instance+"/"+tc.DownloaderID)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("collectCopies(%q, %v) = %v, 期望 %v", tt.instance, tt.clientList, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package vertex
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ==========================================
+// 敏感字段 (Secret)
+// ==========================================
+
+// Secret 密码等敏感字符串。通过 fmt (%v/%s/%q/%#v)、slog 与 JSON 输出时均显示为 "******"，
+// 只有 Reveal 返回原文。SDK 发送请求时会自动使用原文。
+type Secret string
+
+// Reveal 返回原文
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// IsRedacted 判断值是否为脱敏占位内容 (如读取了脱敏的备份)
+func (s Secret) IsRedacted() bool {
+	return s == redactedValue
+}
+
+// ErrRedactedSecret 密码为脱敏占位内容，SDK 拒绝将其发送给 Vertex，以免覆盖真实密码
+var ErrRedactedSecret = errors.New("密码为脱敏内容 \"******\"")
+
+// String 实现 fmt.Stringer，空值保持为空以便区分 "未设置"
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// GoString 实现 fmt.GoStringer (%#v)
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// LogValue 实现 slog.LogValuer
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON 输出脱敏后的内容
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// revealedServer 请求体中使用的 Server，密码为原文 (外层字段覆盖内嵌字段)
+type revealedServer struct {
+	Server
+	Password string `json:"password"`
+}
+
+func (s Server) revealed() revealedServer {
+	return revealedServer{Server: s, Password: s.Password.Reveal()}
+}
+
+// revealedDownloaderConfig 请求体中使用的 DownloaderConfig，密码为原文
+type revealedDownloaderConfig struct {
+	DownloaderConfig
+	Password string `json:"password"`
+}
+
+func (d DownloaderConfig) revealed() revealedDownloaderConfig {
+	return revealedDownloaderConfig{DownloaderConfig: d, Password: d.Password.Reveal()}
+}
+
+// ==========================================
+// 信封加密 (AES-GCM)
+// ==========================================
+
+// EnvelopeAlgorithm 当前使用的加密算法
+const EnvelopeAlgorithm = "AES-256-GCM"
+
+// Envelope 信封加密后的数据：随机生成的数据密钥加密内容，用户提供的主密钥加密数据密钥
+type Envelope struct {
+	Algorithm    string `json:"alg"`
+	EncryptedKey []byte `json:"encryptedKey"` // 主密钥加密的数据密钥 (nonce 在前)
+	Ciphertext   []byte `json:"ciphertext"`   // 数据密钥加密的内容 (nonce 在前)
+}
+
+// ErrDecrypt 密钥错误或数据被篡改
+var ErrDecrypt = errors.New("解密失败: 密钥错误或数据已损坏")
+
+// SealEnvelope 使用主密钥 (16/24/32 字节的 AES 密钥) 加密 plaintext
+func SealEnvelope(key, plaintext []byte) (*Envelope, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	encryptedKey, err := gcmSeal(key, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := gcmSeal(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{Algorithm: EnvelopeAlgorithm, EncryptedKey: encryptedKey, Ciphertext: ciphertext}, nil
+}
+
+// Open 使用主密钥解密
+func (e *Envelope) Open(key []byte) ([]byte, error) {
+	if e.Algorithm != EnvelopeAlgorithm {
+		return nil, fmt.Errorf("不支持的加密算法: %s", e.Algorithm)
+	}
+	dataKey, err := gcmOpen(key, e.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	return gcmOpen(dataKey, e.Ciphertext)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("无效的密钥: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func gcmSeal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func gcmOpen(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
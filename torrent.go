@@ -0,0 +1,181 @@
+package vertex
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// 种子模型 (Torrent Model)
+// ==========================================
+
+// TorrentState 种子状态，取值与下载器 (主要是 qBittorrent) 上报的状态一致
+type TorrentState string
+
+const (
+	StateDownloading TorrentState = "downloading"  // 下载中
+	StateSeeding     TorrentState = "seeding"      // 做种中
+	StateUploading   TorrentState = "uploading"    // 上传中 (qBittorrent 的做种状态)
+	StateStalledUP   TorrentState = "stalledUP"    // 做种中但无上传
+	StateStalledDL   TorrentState = "stalledDL"    // 下载中但无速度
+	StatePausedUP    TorrentState = "pausedUP"     // 已完成并暂停
+	StatePausedDL    TorrentState = "pausedDL"     // 未完成并暂停
+	StateQueuedUP    TorrentState = "queuedUP"     // 排队做种
+	StateQueuedDL    TorrentState = "queuedDL"     // 排队下载
+	StateCheckingUP  TorrentState = "checkingUP"   // 校验中 (已完成)
+	StateCheckingDL  TorrentState = "checkingDL"   // 校验中 (未完成)
+	StateForcedUP    TorrentState = "forcedUP"     // 强制做种
+	StateForcedDL    TorrentState = "forcedDL"     // 强制下载
+	StateMetaDL      TorrentState = "metaDL"       // 获取元数据中
+	StateMoving      TorrentState = "moving"       // 移动数据中
+	StateMissing     TorrentState = "missingFiles" // 文件丢失
+	StateError       TorrentState = "error"        // 错误
+)
+
+// IsSeeding 是否处于做种状态 (含无上传、排队与强制做种)
+func (s TorrentState) IsSeeding() bool {
+	switch s {
+	case StateSeeding, StateUploading, StateStalledUP, StateQueuedUP, StateForcedUP:
+		return true
+	}
+	return false
+}
+
+// IsDownloading 是否处于下载状态 (含无速度、排队、强制下载与获取元数据)
+func (s TorrentState) IsDownloading() bool {
+	switch s {
+	case StateDownloading, StateStalledDL, StateQueuedDL, StateForcedDL, StateMetaDL:
+		return true
+	}
+	return false
+}
+
+// IsPaused 是否已暂停
+func (s TorrentState) IsPaused() bool {
+	return s == StatePausedUP || s == StatePausedDL
+}
+
+// IsError 是否处于错误状态
+func (s TorrentState) IsError() bool {
+	return s == StateError || s == StateMissing
+}
+
+// Added 返回添加时间，未知时返回零值
+func (t *Torrent) Added() time.Time {
+	return unixTime(t.AddedTime)
+}
+
+// Completed 返回完成时间，未完成时返回零值
+func (t *Torrent) Completed() time.Time {
+	return unixTime(t.CompletedTime)
+}
+
+// Age 返回自添加以来经过的时间，添加时间未知时返回 0
+func (t *Torrent) Age() time.Duration {
+	if t.AddedTime <= 0 {
+		return 0
+	}
+	return time.Since(t.Added())
+}
+
+// Ref 返回用于种子操作的定位信息
+func (t *Torrent) Ref() TorrentRef {
+	return TorrentRef{Hash: t.Hash, ClientID: t.ClientID}
+}
+
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// torrentFields Torrent 中已识别的 JSON 字段名
+var torrentFields = jsonFieldNames(reflect.TypeOf(Torrent{}))
+
+// UnmarshalJSON 解析种子信息，兼容字符串形式的标签，并保留未识别的字段
+func (t *Torrent) UnmarshalJSON(data []byte) error {
+	type plain Torrent
+	var aux struct {
+		plain
+		Tags json.RawMessage `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*t = Torrent(aux.plain)
+	t.Tags = parseTags(aux.Tags)
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for k := range torrentFields {
+		delete(all, k)
+	}
+	if len(all) > 0 {
+		t.Extra = all
+	}
+	return nil
+}
+
+// MarshalJSON 序列化种子信息，Extra 中的字段会一并输出，空标签输出为 []
+func (t Torrent) MarshalJSON() ([]byte, error) {
+	type plain Torrent
+	t.Tags = nonNilStrings(t.Tags)
+	data, err := json.Marshal(plain(t))
+	if err != nil || len(t.Extra) == 0 {
+		return data, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range t.Extra {
+		if _, known := torrentFields[k]; !known {
+			all[k] = v
+		}
+	}
+	return json.Marshal(all)
+}
+
+// parseTags 解析标签：支持字符串数组或逗号分隔的字符串
+func parseTags(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil || s == "" {
+		return nil
+	}
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			list = append(list, tag)
+		}
+	}
+	return list
+}
+
+// jsonFieldNames 返回结构体所有导出字段的 JSON 名称
+func jsonFieldNames(typ reflect.Type) map[string]struct{} {
+	names := make(map[string]struct{})
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = struct{}{}
+	}
+	return names
+}
@@ -0,0 +1,185 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ==========================================
+// 种子操作 API (Torrent Actions)
+// ==========================================
+
+// 以下操作由 Vertex 转发给种子所在的下载器执行，请求体统一包含 hash 与 clientId。
+//
+// 接口来源：Vertex 的接口在 app/routes/router.js 中注册 (https://github.com/vertex-app/vertex)。
+// 下列路径沿用 /api/torrent/list、/api/torrent/deleteTorrent 等已有接口的命名，
+// 尚未与 router.js 逐一核对；服务端未注册时请求会失败并返回 *HTTPError (404)：
+//
+//	POST /api/torrent/pause        {hash, clientId}
+//	POST /api/torrent/resume       {hash, clientId}
+//	POST /api/torrent/recheck      {hash, clientId}
+//	POST /api/torrent/reannounce   {hash, clientId}
+//	POST /api/torrent/setCategory  {hash, clientId, category}
+//	POST /api/torrent/setTags      {hash, clientId, tags: string[]}
+//	POST /api/torrent/setLimit     {hash, clientId, uploadLimit, downloadLimit}
+//	POST /api/torrent/setLocation  {hash, clientId, savePath}
+
+// TorrentRef 定位某个下载器中的一个种子
+type TorrentRef struct {
+	Hash     string `json:"hash"`
+	ClientID string `json:"clientId"` // 所属下载器 ID
+}
+
+// TorrentActionResult 批量操作中单个种子的执行结果
+type TorrentActionResult struct {
+	Ref TorrentRef
+	Err error // 成功时为 nil
+}
+
+// BatchResult 批量操作的结果，顺序与传入的种子一致
+type BatchResult []TorrentActionResult
+
+// Failed 返回执行失败的条目
+func (r BatchResult) Failed() []TorrentActionResult {
+	var failed []TorrentActionResult
+	for _, item := range r {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Err 将所有失败条目的错误合并为一个错误，全部成功时返回 nil
+func (r BatchResult) Err() error {
+	var errs []error
+	for _, item := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s@%s: %w", item.Ref.Hash, item.Ref.ClientID, item.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// batchConcurrency 批量操作的最大并发数
+const batchConcurrency = 4
+
+// torrentAction 对单个种子执行操作，extra 为附加的请求参数
+func (c *Client) torrentAction(ctx context.Context, path string, ref TorrentRef, extra map[string]interface{}) error {
+	payload := map[string]interface{}{
+		"hash":     ref.Hash,
+		"clientId": ref.ClientID,
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	_, err := c.post(ctx, path, payload)
+	return err
+}
+
+// batchTorrentAction 对多个种子并发执行同一操作，单个失败不影响其余种子
+func (c *Client) batchTorrentAction(ctx context.Context, path string, refs []TorrentRef, extra map[string]interface{}) BatchResult {
+	res := make(BatchResult, len(refs))
+	parallel(len(refs), batchConcurrency, func(i int) {
+		err := ctx.Err()
+		if err == nil {
+			err = c.torrentAction(ctx, path, refs[i], extra)
+		}
+		res[i] = TorrentActionResult{Ref: refs[i], Err: err}
+	})
+	return res
+}
+
+// PauseTorrent 暂停种子
+func (c *Client) PauseTorrent(ctx context.Context, hash, clientId string) error {
+	return c.torrentAction(ctx, "/api/torrent/pause", TorrentRef{hash, clientId}, nil)
+}
+
+// ResumeTorrent 继续种子
+func (c *Client) ResumeTorrent(ctx context.Context, hash, clientId string) error {
+	return c.torrentAction(ctx, "/api/torrent/resume", TorrentRef{hash, clientId}, nil)
+}
+
+// RecheckTorrent 强制重新校验种子数据
+func (c *Client) RecheckTorrent(ctx context.Context, hash, clientId string) error {
+	return c.torrentAction(ctx, "/api/torrent/recheck", TorrentRef{hash, clientId}, nil)
+}
+
+// ReannounceTorrent 强制向 Tracker 重新汇报
+func (c *Client) ReannounceTorrent(ctx context.Context, hash, clientId string) error {
+	return c.torrentAction(ctx, "/api/torrent/reannounce", TorrentRef{hash, clientId}, nil)
+}
+
+// SetTorrentCategory 设置种子分类，category 为空表示清除分类
+func (c *Client) SetTorrentCategory(ctx context.Context, hash, clientId, category string) error {
+	return c.torrentAction(ctx, "/api/torrent/setCategory", TorrentRef{hash, clientId}, map[string]interface{}{"category": category})
+}
+
+// SetTorrentTags 设置种子标签 (覆盖原有标签)
+func (c *Client) SetTorrentTags(ctx context.Context, hash, clientId string, tags []string) error {
+	return c.torrentAction(ctx, "/api/torrent/setTags", TorrentRef{hash, clientId}, map[string]interface{}{"tags": nonNilStrings(tags)})
+}
+
+// SetTorrentLimits 设置种子上传/下载限速 (B/s)，0 表示不限速
+func (c *Client) SetTorrentLimits(ctx context.Context, hash, clientId string, uploadLimit, downloadLimit int64) error {
+	return c.torrentAction(ctx, "/api/torrent/setLimit", TorrentRef{hash, clientId}, limitPayload(uploadLimit, downloadLimit))
+}
+
+// SetTorrentLocation 修改种子保存路径，下载器会移动已有数据
+func (c *Client) SetTorrentLocation(ctx context.Context, hash, clientId, savePath string) error {
+	return c.torrentAction(ctx, "/api/torrent/setLocation", TorrentRef{hash, clientId}, map[string]interface{}{"savePath": savePath})
+}
+
+// PauseTorrents 批量暂停种子
+func (c *Client) PauseTorrents(ctx context.Context, refs []TorrentRef) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/pause", refs, nil)
+}
+
+// ResumeTorrents 批量继续种子
+func (c *Client) ResumeTorrents(ctx context.Context, refs []TorrentRef) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/resume", refs, nil)
+}
+
+// RecheckTorrents 批量强制校验种子
+func (c *Client) RecheckTorrents(ctx context.Context, refs []TorrentRef) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/recheck", refs, nil)
+}
+
+// ReannounceTorrents 批量重新汇报种子
+func (c *Client) ReannounceTorrents(ctx context.Context, refs []TorrentRef) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/reannounce", refs, nil)
+}
+
+// SetTorrentsCategory 批量设置种子分类
+func (c *Client) SetTorrentsCategory(ctx context.Context, refs []TorrentRef, category string) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/setCategory", refs, map[string]interface{}{"category": category})
+}
+
+// SetTorrentsTags 批量设置种子标签
+func (c *Client) SetTorrentsTags(ctx context.Context, refs []TorrentRef, tags []string) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/setTags", refs, map[string]interface{}{"tags": nonNilStrings(tags)})
+}
+
+// SetTorrentsLimits 批量设置种子限速 (B/s)，0 表示不限速
+func (c *Client) SetTorrentsLimits(ctx context.Context, refs []TorrentRef, uploadLimit, downloadLimit int64) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/setLimit", refs, limitPayload(uploadLimit, downloadLimit))
+}
+
+// SetTorrentsLocation 批量修改种子保存路径
+func (c *Client) SetTorrentsLocation(ctx context.Context, refs []TorrentRef, savePath string) BatchResult {
+	return c.batchTorrentAction(ctx, "/api/torrent/setLocation", refs, map[string]interface{}{"savePath": savePath})
+}
+
+func limitPayload(uploadLimit, downloadLimit int64) map[string]interface{} {
+	return map[string]interface{}{
+		"uploadLimit":   uploadLimit,
+		"downloadLimit": downloadLimit,
+	}
+}
+
+// nonNilStrings 保证空切片序列化为 [] 而不是 null
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,163 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bodyRecorder 记录各接口收到的 JSON 请求体，clientId 为 failClient 的请求返回业务错误
+type bodyRecorder struct {
+	mu         sync.Mutex
+	bodies     map[string][]map[string]interface{}
+	failClient string
+}
+
+func (b *bodyRecorder) route(r *http.Request) (interface{}, error) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.bodies == nil {
+		b.bodies = make(map[string][]map[string]interface{})
+	}
+	b.bodies[r.URL.Path] = append(b.bodies[r.URL.Path], body)
+	if b.failClient != "" && body["clientId"] == b.failClient {
+		return nil, errors.New("下载器离线")
+	}
+	return nil, nil
+}
+
+func (b *bodyRecorder) get(path string) []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bodies[path]
+}
+
+// actionRoutes 所有种子操作接口都由 rec 处理
+func actionRoutes(rec *bodyRecorder) map[string]testRoute {
+	routes := make(map[string]testRoute)
+	for _, action := range []string{"pause", "resume", "recheck", "reannounce", "setCategory", "setTags", "setLimit", "setLocation"} {
+		routes["/api/torrent/"+action] = rec.route
+	}
+	return routes
+}
+
+func TestTorrentActionBodies(t *testing.T) {
+	rec := &bodyRecorder{}
+	c, _ := newTestClient(t, actionRoutes(rec))
+	ctx := context.Background()
+
+	tests := []struct {
+		path string
+		call func() error
+		want map[string]interface{}
+	}{
+		{"/api/torrent/pause", func() error { return c.PauseTorrent(ctx, "h1", "qb") }, nil},
+		{"/api/torrent/resume", func() error { return c.ResumeTorrent(ctx, "h1", "qb") }, nil},
+		{"/api/torrent/recheck", func() error { return c.RecheckTorrent(ctx, "h1", "qb") }, nil},
+		{"/api/torrent/reannounce", func() error { return c.ReannounceTorrent(ctx, "h1", "qb") }, nil},
+		{"/api/torrent/setCategory", func() error { return c.SetTorrentCategory(ctx, "h1", "qb", "movies") },
+			map[string]interface{}{"category": "movies"}},
+		{"/api/torrent/setTags", func() error { return c.SetTorrentTags(ctx, "h1", "qb", []string{"hd", "keep"}) },
+			map[string]interface{}{"tags": []interface{}{"hd", "keep"}}},
+		{"/api/torrent/setLimit", func() error { return c.SetTorrentLimits(ctx, "h1", "qb", 1024, 0) },
+			map[string]interface{}{"uploadLimit": float64(1024), "downloadLimit": float64(0)}},
+		{"/api/torrent/setLocation", func() error { return c.SetTorrentLocation(ctx, "h1", "qb", "/data/done") },
+			map[string]interface{}{"savePath": "/data/done"}},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		want := map[string]interface{}{"hash": "h1", "clientId": "qb"}
+		for k, v := range tt.want {
+			want[k] = v
+		}
+		bodies := rec.get(tt.path)
+		if len(bodies) != 1 || !reflect.DeepEqual(bodies[0], want) {
+			t.Errorf("%s 请求体: %v, 期望 %v", tt.path, bodies, want)
+		}
+	}
+}
+
+func TestSetTorrentTagsEmpty(t *testing.T) {
+	rec := &bodyRecorder{}
+	c, _ := newTestClient(t, actionRoutes(rec))
+	if err := c.SetTorrentTags(context.Background(), "h1", "qb", nil); err != nil {
+		t.Fatal(err)
+	}
+	// 清空标签时发送 [] 而不是 null
+	if tags, ok := rec.get("/api/torrent/setTags")[0]["tags"].([]interface{}); !ok || len(tags) != 0 {
+		t.Fatalf("tags 应为空数组: %v", rec.get("/api/torrent/setTags"))
+	}
+}
+
+func TestBatchResultPartialFailure(t *testing.T) {
+	rec := &bodyRecorder{failClient: "tr"}
+	c, _ := newTestClient(t, actionRoutes(rec))
+	refs := []TorrentRef{{"h1", "qb"}, {"h2", "tr"}, {"h3", "qb"}, {"h4", "tr"}, {"h5", "qb"}}
+
+	res := c.SetTorrentsCategory(context.Background(), refs, "tv")
+	if len(res) != len(refs) {
+		t.Fatalf("结果数量 %d, 期望 %d", len(res), len(refs))
+	}
+	// 结果顺序与传入的种子一致
+	for i, item := range res {
+		if item.Ref != refs[i] {
+			t.Fatalf("第 %d 项为 %v, 期望 %v", i, item.Ref, refs[i])
+		}
+		if failed := item.Ref.ClientID == "tr"; (item.Err != nil) != failed {
+			t.Errorf("%v: 错误 %v", item.Ref, item.Err)
+		}
+	}
+	if bodies := rec.get("/api/torrent/setCategory"); len(bodies) != len(refs) {
+		t.Fatalf("单个失败不应影响其余种子，实际请求 %d 次", len(bodies))
+	}
+
+	failed := res.Failed()
+	if len(failed) != 2 || failed[0].Ref.Hash != "h2" || failed[1].Ref.Hash != "h4" {
+		t.Fatalf("失败条目不符合预期: %v", failed)
+	}
+	var apiErr *APIError
+	if err := res.Err(); !errors.As(err, &apiErr) || !strings.HasPrefix(err.Error(), "h2@tr: ") || !strings.Contains(err.Error(), "\nh4@tr: ") {
+		t.Fatalf("合并后的错误不符合预期: %v", err)
+	}
+
+	if err := c.PauseTorrents(context.Background(), refs[:1]).Err(); err != nil {
+		t.Fatalf("全部成功时 Err 应返回 nil: %v", err)
+	}
+}
+
+func TestBatchTorrentActionCancel(t *testing.T) {
+	rec := &bodyRecorder{}
+	c, _ := newTestClient(t, actionRoutes(rec))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res := c.ResumeTorrents(ctx, []TorrentRef{{"h1", "qb"}, {"h2", "qb"}})
+	for _, item := range res {
+		if !errors.Is(item.Err, context.Canceled) {
+			t.Errorf("%v 应记录为取消错误，实际: %v", item.Ref, item.Err)
+		}
+	}
+	if n := len(rec.get("/api/torrent/resume")); n != 0 {
+		t.Fatalf("已取消的 ctx 不应发起请求，实际 %d 次", n)
+	}
+}
+
+func TestTorrentActionNotFound(t *testing.T) {
+	// 服务端未注册的接口返回 404
+	c, _ := newTestClient(t, nil)
+	var httpErr *HTTPError
+	if err := c.RecheckTorrent(context.Background(), "h1", "qb"); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("期望 404 错误，实际: %v", err)
+	}
+}
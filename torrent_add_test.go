@@ -0,0 +1,215 @@
+package vertex
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMagnetInfoHash(t *testing.T) {
+	const hash = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+	tests := []struct {
+		name   string
+		magnet string
+		want   string
+	}{
+		{"十六进制", "magnet:?xt=urn:btih:" + hash + "&dn=ubuntu", hash},
+		{"大写十六进制", "magnet:?xt=urn:btih:" + strings.ToUpper(hash), hash},
+		{"base32", "magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK", hash},
+		{"小写 base32", "magnet:?xt=urn:btih:yex6dqdlxisuvhoj6um3gnnkpqjwpkek", hash},
+		{"前缀大小写不敏感", "magnet:?xt=URN:BTIH:" + hash, hash},
+		{"跳过其他 xt", "magnet:?xt=urn:sha1:abc&xt=urn:btih:" + hash, hash},
+	}
+	for _, tt := range tests {
+		got, err := magnetInfoHash(tt.magnet)
+		if err != nil {
+			t.Errorf("%s: 出错: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: 得到 %s, 期望 %s", tt.name, got, tt.want)
+		}
+	}
+
+	for name, magnet := range map[string]string{
+		"缺少 xt":       "magnet:?dn=ubuntu",
+		"只有其他 xt":     "magnet:?xt=urn:sha1:abc",
+		"十六进制长度不足":    "magnet:?xt=urn:btih:" + hash[:39],
+		"十六进制长度过长":    "magnet:?xt=urn:btih:" + hash + "0",
+		"非十六进制字符":     "magnet:?xt=urn:btih:" + strings.Repeat("z", 40),
+		"base32 长度不足": "magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKE",
+		"base32 非法字符": "magnet:?xt=urn:btih:" + strings.Repeat("1", 32),
+		"空 InfoHash":  "magnet:?xt=urn:btih:",
+		"magnet 格式错误": "magnet:?xt=%zz",
+	} {
+		if got, err := magnetInfoHash(magnet); err == nil {
+			t.Errorf("%s: 期望出错，实际: %s", name, got)
+		}
+	}
+}
+
+// testTorrent 返回一个最小的单文件 .torrent 及其 InfoHash
+func testTorrent() ([]byte, string) {
+	info := "d6:lengthi1024e4:name8:test.bin12:piece lengthi16384e6:pieces20:" + strings.Repeat("x", 20) + "e"
+	sum := sha1.Sum([]byte(info))
+	return []byte("d8:announce21:http://t.example/anno4:info" + info + "e"), hex.EncodeToString(sum[:])
+}
+
+// addTorrentRecorder 记录 /api/torrent/addTorrent 的请求
+type addTorrentRecorder struct {
+	mu          sync.Mutex
+	contentType string
+	fields      map[string]string
+	fileName    string
+	fileData    []byte
+	json        map[string]interface{}
+}
+
+func (a *addTorrentRecorder) route(r *http.Request) (interface{}, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.contentType = r.Header.Get("Content-Type")
+	if strings.HasPrefix(a.contentType, "multipart/form-data") {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			return nil, err
+		}
+		a.fields = make(map[string]string)
+		for k, v := range r.MultipartForm.Value {
+			a.fields[k] = v[0]
+		}
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		a.fileName = h.Filename
+		a.fileData, err = io.ReadAll(f)
+		return nil, err
+	}
+	return nil, json.NewDecoder(r.Body).Decode(&a.json)
+}
+
+func TestAddTorrentUpload(t *testing.T) {
+	rec := &addTorrentRecorder{}
+	c, _ := newTestClient(t, map[string]testRoute{"/api/torrent/addTorrent": rec.route})
+	data, want := testTorrent()
+
+	hash, err := c.AddTorrent(context.Background(), "qb", TorrentFromFile("linux.torrent", bytes.NewReader(data)), AddTorrentOption{
+		Category:    "iso",
+		SavePath:    "/data",
+		Paused:      true,
+		UploadLimit: 1024,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != want {
+		t.Fatalf("InfoHash = %s, 期望 %s", hash, want)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	wantFields := map[string]string{
+		"clientId":      "qb",
+		"category":      "iso",
+		"savePath":      "/data",
+		"paused":        "true",
+		"skipChecking":  "false",
+		"uploadLimit":   "1024",
+		"downloadLimit": "0",
+	}
+	if len(rec.fields) != len(wantFields) {
+		t.Fatalf("表单字段: %v, 期望 %v", rec.fields, wantFields)
+	}
+	for k, v := range wantFields {
+		if rec.fields[k] != v {
+			t.Errorf("表单字段 %s = %q, 期望 %q", k, rec.fields[k], v)
+		}
+	}
+	if rec.fileName != "linux.torrent" || !bytes.Equal(rec.fileData, data) {
+		t.Fatalf("上传的文件不符合预期: %s (%d 字节)", rec.fileName, len(rec.fileData))
+	}
+}
+
+func TestAddTorrentUploadDefaultName(t *testing.T) {
+	rec := &addTorrentRecorder{}
+	c, _ := newTestClient(t, map[string]testRoute{"/api/torrent/addTorrent": rec.route})
+	data, _ := testTorrent()
+	if _, err := c.AddTorrent(context.Background(), "qb", TorrentSource{File: bytes.NewReader(data)}, AddTorrentOption{}); err != nil {
+		t.Fatal(err)
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.fileName != "upload.torrent" {
+		t.Fatalf("未指定文件名时应使用 upload.torrent，实际 %q", rec.fileName)
+	}
+}
+
+func TestAddTorrentURL(t *testing.T) {
+	const magnet = "magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK&dn=ubuntu"
+	tests := []struct {
+		name, url, hash string
+	}{
+		{"magnet", magnet, "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"},
+		{"下载链接", "https://pt.example/download.php?id=1", ""}, // 由 Vertex 下载，无法在本地计算
+	}
+	for _, tt := range tests {
+		rec := &addTorrentRecorder{}
+		c, _ := newTestClient(t, map[string]testRoute{"/api/torrent/addTorrent": rec.route})
+		hash, err := c.AddTorrent(context.Background(), "qb", TorrentFromURL(tt.url), AddTorrentOption{SkipChecking: true, DownloadLimit: 2048})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if hash != tt.hash {
+			t.Errorf("%s: InfoHash = %q, 期望 %q", tt.name, hash, tt.hash)
+		}
+
+		rec.mu.Lock()
+		want := map[string]interface{}{
+			"clientId":      "qb",
+			"url":           tt.url,
+			"category":      "",
+			"savePath":      "",
+			"paused":        false,
+			"skipChecking":  true,
+			"uploadLimit":   float64(0),
+			"downloadLimit": float64(2048),
+		}
+		if !strings.HasPrefix(rec.contentType, "application/json") {
+			t.Errorf("%s: Content-Type = %s", tt.name, rec.contentType)
+		}
+		if len(rec.json) != len(want) {
+			t.Errorf("%s: 请求体 %v, 期望 %v", tt.name, rec.json, want)
+		}
+		for k, v := range want {
+			if rec.json[k] != v {
+				t.Errorf("%s: %s = %v, 期望 %v", tt.name, k, rec.json[k], v)
+			}
+		}
+		rec.mu.Unlock()
+	}
+}
+
+func TestAddTorrentInvalidSource(t *testing.T) {
+	c, srv := newTestClient(t, map[string]testRoute{"/api/torrent/addTorrent": staticRoute(nil)})
+	ctx := context.Background()
+	for name, src := range map[string]TorrentSource{
+		"未指定来源":      {},
+		"无效的种子文件":    TorrentFromFile("bad.torrent", strings.NewReader("not bencode")),
+		"无效的 magnet": TorrentFromURL("magnet:?dn=missing-xt"),
+	} {
+		if _, err := c.AddTorrent(ctx, "qb", src, AddTorrentOption{}); err == nil {
+			t.Errorf("%s: 期望出错", name)
+		}
+	}
+	if n := srv.Calls("/api/torrent/addTorrent"); n != 0 {
+		t.Fatalf("来源无效时不应发起请求，实际请求 %d 次", n)
+	}
+}
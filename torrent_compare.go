@@ -0,0 +1,88 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iniwex5/vertex-go-sdk/metainfo"
+)
+
+// ==========================================
+// 本地种子文件比对 (Compare)
+// ==========================================
+
+// TorrentComparison 本地种子文件与 Vertex 中种子的比对结果
+type TorrentComparison struct {
+	Local  *metainfo.MetaInfo // 本地种子文件
+	Remote *Torrent           // Vertex 中的种子，不存在时为 nil
+	Diffs  []string           // 不一致的字段说明
+}
+
+// Found 种子是否存在于 Vertex 中
+func (r *TorrentComparison) Found() bool {
+	return r.Remote != nil
+}
+
+// Match 种子存在且所有比对字段一致
+func (r *TorrentComparison) Match() bool {
+	return r.Remote != nil && len(r.Diffs) == 0
+}
+
+// CompareTorrent 比对本地种子文件与 Vertex 返回的种子 (Hash、名称、大小)
+func CompareTorrent(m *metainfo.MetaInfo, t *Torrent) *TorrentComparison {
+	res := &TorrentComparison{Local: m, Remote: t}
+	if t == nil {
+		return res
+	}
+	if !strings.EqualFold(m.InfoHash, t.Hash) {
+		res.Diffs = append(res.Diffs, fmt.Sprintf("Hash 不一致: 本地 %s, 远端 %s", m.InfoHash, t.Hash))
+	}
+	if m.Name != t.Name {
+		res.Diffs = append(res.Diffs, fmt.Sprintf("名称不一致: 本地 %q, 远端 %q", m.Name, t.Name))
+	}
+	if size := m.TotalSize(); size != t.Size {
+		res.Diffs = append(res.Diffs, fmt.Sprintf("大小不一致: 本地 %d, 远端 %d", size, t.Size))
+	}
+	return res
+}
+
+// torrentNotFoundMessages Vertex 查询不到种子时业务错误中常见的信息
+var torrentNotFoundMessages = []string{
+	"not found",
+	"does not exist",
+	"不存在",
+	"未找到",
+}
+
+// isTorrentNotFound 判断错误是否为 Vertex 报告种子不存在；未登录、参数错误等其他业务错误返回 false
+func isTorrentNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	msg := strings.ToLower(apiErr.Message)
+	for _, m := range torrentNotFoundMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareTorrentFile 通过 InfoHash 查询 Vertex 中的种子并与本地种子文件比对。
+// 种子不存在时返回 Found() 为 false 的结果，其余错误 (包括其他业务错误) 原样返回。
+func (c *Client) CompareTorrentFile(ctx context.Context, m *metainfo.MetaInfo) (*TorrentComparison, error) {
+	t, err := c.GetTorrentInfo(ctx, m.InfoHash)
+	if err != nil {
+		if isTorrentNotFound(err) {
+			return CompareTorrent(m, nil), nil
+		}
+		return nil, err
+	}
+	if t.Hash == "" {
+		return CompareTorrent(m, nil), nil
+	}
+	return CompareTorrent(m, t), nil
+}
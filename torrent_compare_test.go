@@ -0,0 +1,69 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/iniwex5/vertex-go-sdk/metainfo"
+)
+
+func TestCompareTorrentFile(t *testing.T) {
+	local := &metainfo.MetaInfo{
+		InfoHash: "abc",
+		Name:     "Movie",
+		Files:    []metainfo.File{{Path: []string{"Movie"}, Length: 100}},
+	}
+	tests := []struct {
+		name      string
+		route     testRoute
+		wantErr   bool
+		wantFound bool
+		wantMatch bool
+	}{
+		{
+			name:      "一致",
+			route:     staticRoute(Torrent{Hash: "ABC", Name: "Movie", Size: 100}),
+			wantFound: true,
+			wantMatch: true,
+		},
+		{
+			name:      "大小不一致",
+			route:     staticRoute(Torrent{Hash: "abc", Name: "Movie", Size: 99}),
+			wantFound: true,
+		},
+		{
+			name:  "返回空种子",
+			route: staticRoute(Torrent{}),
+		},
+		{
+			name:  "种子不存在",
+			route: func(*http.Request) (interface{}, error) { return nil, errors.New("种子不存在") },
+		},
+		{
+			name:    "其他业务错误不视为不存在",
+			route:   func(*http.Request) (interface{}, error) { return nil, errors.New("未登录") },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, map[string]testRoute{"/api/torrent/info": tt.route})
+			res, err := c.CompareTorrentFile(context.Background(), local)
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("err = %v, 期望 APIError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Found() != tt.wantFound || res.Match() != tt.wantMatch {
+				t.Errorf("Found = %v Match = %v (%v), 期望 %v %v", res.Found(), res.Match(), res.Diffs, tt.wantFound, tt.wantMatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// ==========================================
+// 种子文件、Tracker 与 Peer 详情 (Torrent Detail)
+// ==========================================
+
+// TorrentFile 种子中的单个文件
+type TorrentFile struct {
+	Index    int     `json:"index"`    // 文件序号
+	Name     string  `json:"name"`     // 相对于保存路径的文件路径
+	Size     int64   `json:"size"`     // 文件大小 (字节)
+	Progress float64 `json:"progress"` // 进度 (0-1)
+	Priority int     `json:"priority"` // 下载优先级，0 表示不下载
+}
+
+// FullPath 返回文件在下载器所在机器上的完整路径
+func (f TorrentFile) FullPath(savePath string) string {
+	return path.Join(savePath, f.Name)
+}
+
+// TrackerStatus Tracker 状态，取值与 qBittorrent 一致
+type TrackerStatus int
+
+const (
+	TrackerDisabled     TrackerStatus = 0 // 已禁用 (如 DHT/PeX/LSD)
+	TrackerNotContacted TrackerStatus = 1 // 尚未连接
+	TrackerWorking      TrackerStatus = 2 // 工作正常
+	TrackerUpdating     TrackerStatus = 3 // 正在汇报
+	TrackerNotWorking   TrackerStatus = 4 // 无法工作
+)
+
+// TorrentTracker 种子的 Tracker 信息
+type TorrentTracker struct {
+	URL      string        `json:"url"`
+	Status   TrackerStatus `json:"status"`
+	Tier     int           `json:"tier"`
+	Seeders  int           `json:"numSeeds"`
+	Leechers int           `json:"numLeeches"`
+	Peers    int           `json:"numPeers"`
+	Message  string        `json:"msg"` // Tracker 返回的信息，如 "Unregistered torrent"
+}
+
+// unregisteredMessages Tracker 表示种子已被站点删除时常见的返回信息
+var unregisteredMessages = []string{
+	"unregistered",
+	"not registered",
+	"torrent not found",
+	"torrent does not exist",
+	"infohash not found",
+	"种子不存在",
+	"未注册",
+}
+
+// IsUnregistered 判断 Tracker 是否报告种子未注册 (通常意味着种子已被站点删除)
+func (t TorrentTracker) IsUnregistered() bool {
+	msg := strings.ToLower(t.Message)
+	for _, m := range unregisteredMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// TorrentPeer 种子的连接节点
+type TorrentPeer struct {
+	IP            string  `json:"ip"`
+	Port          int     `json:"port"`
+	Client        string  `json:"client"`        // 对端客户端名称
+	Country       string  `json:"country"`       // 对端所在国家/地区
+	Progress      float64 `json:"progress"`      // 对端进度 (0-1)
+	UploadSpeed   int64   `json:"uploadSpeed"`   // 向对端上传速度 (B/s)
+	DownloadSpeed int64   `json:"downloadSpeed"` // 从对端下载速度 (B/s)
+	Flags         string  `json:"flags"`         // 连接标记
+}
+
+// GetTorrentFiles 获取种子的文件列表
+func (c *Client) GetTorrentFiles(ctx context.Context, hash, clientId string) ([]TorrentFile, error) {
+	var files []TorrentFile
+	if err := c.getTorrentDetail(ctx, "/api/torrent/files", hash, clientId, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// GetTorrentTrackers 获取种子的 Tracker 列表及状态
+func (c *Client) GetTorrentTrackers(ctx context.Context, hash, clientId string) ([]TorrentTracker, error) {
+	var trackers []TorrentTracker
+	if err := c.getTorrentDetail(ctx, "/api/torrent/trackers", hash, clientId, &trackers); err != nil {
+		return nil, err
+	}
+	return trackers, nil
+}
+
+// GetTorrentPeers 获取种子当前连接的节点 (取决于下载器是否支持)
+func (c *Client) GetTorrentPeers(ctx context.Context, hash, clientId string) ([]TorrentPeer, error) {
+	var peers []TorrentPeer
+	if err := c.getTorrentDetail(ctx, "/api/torrent/peers", hash, clientId, &peers); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// IsTorrentUnregistered 检查种子是否有 Tracker 报告未注册
+func (c *Client) IsTorrentUnregistered(ctx context.Context, hash, clientId string) (bool, error) {
+	trackers, err := c.GetTorrentTrackers(ctx, hash, clientId)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range trackers {
+		if t.IsUnregistered() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *Client) getTorrentDetail(ctx context.Context, path, hash, clientId string, out interface{}) error {
+	resp, err := c.get(ctx, path, map[string]string{"hash": hash, "clientId": clientId})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp.Data, out)
+}
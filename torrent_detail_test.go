@@ -0,0 +1,217 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// detailRoute 校验 hash 与 clientId 查询参数后返回 data
+func detailRoute(t *testing.T, data interface{}) testRoute {
+	return func(r *http.Request) (interface{}, error) {
+		q := r.URL.Query()
+		if q.Get("hash") != "h1" || q.Get("clientId") != "qb" {
+			t.Errorf("%s 查询参数不符合预期: %s", r.URL.Path, r.URL.RawQuery)
+		}
+		return data, nil
+	}
+}
+
+// deleteRecorder 记录 /api/torrent/deleteTorrent 收到的请求体
+func deleteRecorder(bodies chan<- map[string]interface{}) testRoute {
+	return func(r *http.Request) (interface{}, error) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		bodies <- body
+		return nil, nil
+	}
+}
+
+func TestGetTorrentFiles(t *testing.T) {
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/torrent/files": detailRoute(t, []map[string]interface{}{
+			{"index": 0, "name": "Movie/movie.mkv", "size": 1 << 30, "progress": 0.5, "priority": 1},
+			{"index": 1, "name": "Movie/sample.mkv", "size": 1024, "progress": 0, "priority": 0},
+		}),
+	})
+	files, err := c.GetTorrentFiles(context.Background(), "h1", "qb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("期望 2 个文件，实际: %+v", files)
+	}
+	if f := files[0]; f.Name != "Movie/movie.mkv" || f.Size != 1<<30 || f.Progress != 0.5 || f.Priority != 1 {
+		t.Fatalf("文件信息不符合预期: %+v", f)
+	}
+	if files[1].Index != 1 || files[1].Priority != 0 {
+		t.Fatalf("文件信息不符合预期: %+v", files[1])
+	}
+	if p := files[0].FullPath("/data/"); p != "/data/Movie/movie.mkv" {
+		t.Fatalf("完整路径为 %q", p)
+	}
+}
+
+func TestGetTorrentTrackers(t *testing.T) {
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/torrent/trackers": detailRoute(t, []map[string]interface{}{
+			{"url": "** [DHT] **", "status": 0, "tier": -1},
+			{"url": "https://tracker.example/announce", "status": 4, "tier": 0,
+				"numSeeds": 3, "numLeeches": 1, "numPeers": 5, "msg": "Unregistered torrent"},
+		}),
+	})
+	trackers, err := c.GetTorrentTrackers(context.Background(), "h1", "qb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trackers) != 2 {
+		t.Fatalf("期望 2 个 Tracker，实际: %+v", trackers)
+	}
+	tr := trackers[1]
+	if tr.Status != TrackerNotWorking || tr.Seeders != 3 || tr.Leechers != 1 || tr.Peers != 5 || tr.Message != "Unregistered torrent" {
+		t.Fatalf("Tracker 信息不符合预期: %+v", tr)
+	}
+	if trackers[0].Status != TrackerDisabled || trackers[0].Tier != -1 {
+		t.Fatalf("Tracker 信息不符合预期: %+v", trackers[0])
+	}
+}
+
+func TestGetTorrentPeers(t *testing.T) {
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/torrent/peers": detailRoute(t, []map[string]interface{}{
+			{"ip": "10.0.0.2", "port": 51413, "client": "Transmission 4.0", "country": "CN",
+				"progress": 1, "uploadSpeed": 2048, "downloadSpeed": 0, "flags": "D X"},
+		}),
+	})
+	peers, err := c.GetTorrentPeers(context.Background(), "h1", "qb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TorrentPeer{IP: "10.0.0.2", Port: 51413, Client: "Transmission 4.0", Country: "CN",
+		Progress: 1, UploadSpeed: 2048, Flags: "D X"}
+	if len(peers) != 1 || peers[0] != want {
+		t.Fatalf("Peer 信息: %+v, 期望 %+v", peers, want)
+	}
+
+	// 下载器不支持时返回业务错误
+	c, _ = newTestClient(t, map[string]testRoute{
+		"/api/torrent/peers": func(*http.Request) (interface{}, error) {
+			return nil, errors.New("下载器不支持")
+		},
+	})
+	var apiErr *APIError
+	if _, err := c.GetTorrentPeers(context.Background(), "h1", "qb"); !errors.As(err, &apiErr) {
+		t.Fatalf("期望业务错误，实际: %v", err)
+	}
+}
+
+func TestTrackerIsUnregistered(t *testing.T) {
+	tests := map[string]bool{
+		"":                     false,
+		"Working":              false,
+		"Unregistered torrent": true,
+		"UNREGISTERED TORRENT": true,
+		"Torrent not registered with this tracker": true,
+		"torrent not found":                        true,
+		"Torrent does not exist.":                  true,
+		"infohash not found":                       true,
+		"种子不存在":                                    true,
+		"该种子未注册":                                   true,
+		"Timed out":                                false,
+		"tracker is down":                          false,
+	}
+	for msg, want := range tests {
+		if got := (TorrentTracker{Message: msg}).IsUnregistered(); got != want {
+			t.Errorf("IsUnregistered(%q) = %v, 期望 %v", msg, got, want)
+		}
+	}
+}
+
+func TestIsTorrentUnregistered(t *testing.T) {
+	for _, tt := range []struct {
+		msgs []string
+		want bool
+	}{
+		{[]string{"", "Working"}, false},
+		{[]string{"", "Unregistered torrent"}, true},
+		{nil, false},
+	} {
+		var trackers []map[string]interface{}
+		for _, msg := range tt.msgs {
+			trackers = append(trackers, map[string]interface{}{"url": "https://t.example", "msg": msg})
+		}
+		c, _ := newTestClient(t, map[string]testRoute{"/api/torrent/trackers": detailRoute(t, trackers)})
+		got, err := c.IsTorrentUnregistered(context.Background(), "h1", "qb")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Tracker 信息 %q: 得到 %v, 期望 %v", tt.msgs, got, tt.want)
+		}
+	}
+}
+
+func TestDeleteTorrentKeepsFiles(t *testing.T) {
+	bodies := make(chan map[string]interface{}, 1)
+	c, srv := newTestClient(t, map[string]testRoute{
+		"/api/torrent/files":         detailRoute(t, []map[string]interface{}{{"name": "a.mkv"}}),
+		"/api/torrent/deleteTorrent": deleteRecorder(bodies),
+	})
+	// deleteFiles 仅为兼容保留，不会删除数据文件
+	if err := c.DeleteTorrent(context.Background(), "h1", "qb", true); err != nil {
+		t.Fatal(err)
+	}
+	body := <-bodies
+	if body["hash"] != "h1" || body["clientId"] != "qb" {
+		t.Fatalf("请求体不符合预期: %v", body)
+	}
+	if files, ok := body["files"].([]interface{}); !ok || len(files) != 0 {
+		t.Fatalf("DeleteTorrent 不应传递文件列表: %v", body["files"])
+	}
+	if n := srv.Calls("/api/torrent/files"); n != 0 {
+		t.Fatalf("DeleteTorrent 不应获取文件列表，实际请求 %d 次", n)
+	}
+}
+
+func TestDeleteTorrentAndFiles(t *testing.T) {
+	bodies := make(chan map[string]interface{}, 1)
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/torrent/files": detailRoute(t, []map[string]interface{}{
+			{"index": 0, "name": "Movie/movie.mkv", "size": 100},
+			{"index": 1, "name": "Movie/sample.mkv", "size": 10},
+		}),
+		"/api/torrent/deleteTorrent": deleteRecorder(bodies),
+	})
+	if err := c.DeleteTorrentAndFiles(context.Background(), "h1", "qb"); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := (<-bodies)["files"].([]interface{})
+	if len(files) != 2 {
+		t.Fatalf("应传递全部文件，实际: %v", files)
+	}
+	if f, _ := files[1].(map[string]interface{}); f["name"] != "Movie/sample.mkv" {
+		t.Fatalf("文件信息不符合预期: %v", files[1])
+	}
+}
+
+func TestDeleteTorrentAndFilesRefuses(t *testing.T) {
+	for name, files := range map[string]testRoute{
+		"获取失败": func(*http.Request) (interface{}, error) { return nil, errors.New("下载器离线") },
+		"列表为空": staticRoute([]interface{}{}),
+	} {
+		c, srv := newTestClient(t, map[string]testRoute{
+			"/api/torrent/files":         files,
+			"/api/torrent/deleteTorrent": staticRoute(nil),
+		})
+		if err := c.DeleteTorrentAndFiles(context.Background(), "h1", "qb"); err == nil {
+			t.Errorf("%s: 期望出错", name)
+		}
+		if n := srv.Calls("/api/torrent/deleteTorrent"); n != 0 {
+			t.Errorf("%s: 不应执行删除，实际请求 %d 次", name, n)
+		}
+	}
+}
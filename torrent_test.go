@@ -0,0 +1,113 @@
+package vertex
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTorrentTags(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{"数组", `{"tags":["hd","keep"]}`, []string{"hd", "keep"}},
+		{"逗号分隔字符串", `{"tags":"hd, keep,"}`, []string{"hd", "keep"}},
+		{"空字符串", `{"tags":""}`, nil},
+		{"null", `{"tags":null}`, nil},
+		{"缺少字段", `{}`, nil},
+		{"无法识别的类型", `{"tags":1}`, nil},
+	}
+	for _, tt := range tests {
+		var tor Torrent
+		if err := json.Unmarshal([]byte(tt.json), &tor); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(tor.Tags, tt.want) {
+			t.Errorf("%s: Tags = %#v, 期望 %#v", tt.name, tor.Tags, tt.want)
+		}
+	}
+}
+
+func TestTorrentJSONRoundTrip(t *testing.T) {
+	in := `{"hash":"h1","name":"Movie","clientId":"qb","tags":"hd,keep","addedTime":1700000000,"completedTime":0,"eta":8640000,"site":{"name":"pt"}}`
+	var tor Torrent
+	if err := json.Unmarshal([]byte(in), &tor); err != nil {
+		t.Fatal(err)
+	}
+	if tor.Hash != "h1" || tor.ClientID != "qb" || tor.AddedTime != 1700000000 {
+		t.Fatalf("已识别字段解析错误: %+v", tor)
+	}
+	// 已识别的字段不会出现在 Extra 中
+	if len(tor.Extra) != 2 || string(tor.Extra["eta"]) != "8640000" || string(tor.Extra["site"]) != `{"name":"pt"}` {
+		t.Fatalf("Extra 不符合预期: %v", tor.Extra)
+	}
+
+	data, err := json.Marshal(tor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["eta"] != float64(8640000) || !reflect.DeepEqual(out["site"], map[string]interface{}{"name": "pt"}) {
+		t.Fatalf("Extra 中的字段应原样输出: %s", data)
+	}
+	// 标签统一输出为数组
+	if !reflect.DeepEqual(out["tags"], []interface{}{"hd", "keep"}) {
+		t.Fatalf("tags = %v, 期望数组", out["tags"])
+	}
+
+	var again Torrent
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, tor) {
+		t.Fatalf("往返后不一致:\n%+v\n%+v", again, tor)
+	}
+}
+
+func TestTorrentMarshalExtraDoesNotOverride(t *testing.T) {
+	tor := Torrent{
+		Hash:  "h1",
+		Extra: map[string]json.RawMessage{"hash": json.RawMessage(`"stale"`), "eta": json.RawMessage(`1`)},
+	}
+	data, err := json.Marshal(tor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	// Extra 中与已识别字段同名的键不会覆盖结构体字段
+	if out["hash"] != "h1" || out["eta"] != float64(1) {
+		t.Fatalf("输出不符合预期: %s", data)
+	}
+	// 未设置标签时输出 [] 而不是 null
+	if tags, ok := out["tags"].([]interface{}); !ok || len(tags) != 0 {
+		t.Fatalf("tags 应为空数组: %s", data)
+	}
+}
+
+func TestTorrentTimes(t *testing.T) {
+	added := time.Now().Add(-time.Hour).Unix()
+	tor := Torrent{AddedTime: added, CompletedTime: added + 60}
+	if !tor.Added().Equal(time.Unix(added, 0)) || !tor.Completed().Equal(time.Unix(added+60, 0)) {
+		t.Fatalf("时间解析错误: %v / %v", tor.Added(), tor.Completed())
+	}
+	if age := tor.Age(); age < time.Hour || age > time.Hour+time.Minute {
+		t.Fatalf("Age = %v, 期望约 1 小时", age)
+	}
+
+	// 0 或负数表示未知 / 未完成
+	for _, sec := range []int64{0, -1} {
+		unknown := Torrent{AddedTime: sec, CompletedTime: sec}
+		if !unknown.Added().IsZero() || !unknown.Completed().IsZero() || unknown.Age() != 0 {
+			t.Errorf("时间戳 %d 应视为未知: %v / %v / %v", sec, unknown.Added(), unknown.Completed(), unknown.Age())
+		}
+	}
+}
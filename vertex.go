@@ -1,11 +1,13 @@
 package vertex
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -20,14 +22,42 @@ type Response struct {
 	Success bool            `json:"success"` // 请求是否成功
 	Message string          `json:"message"` // 错误信息或提示信息
 	Data    json.RawMessage `json:"data"`    // 具体的响应数据
+
+	StatusCode int `json:"-"` // HTTP 状态码
+}
+
+// HTTPError 表示服务端返回了非 2xx 的 HTTP 状态码
+type HTTPError struct {
+	StatusCode int           // HTTP 状态码
+	Status     string        // HTTP 状态描述
+	RetryAfter time.Duration // 服务端通过 Retry-After 建议的等待时间
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP 错误: %d %s", e.StatusCode, e.Status)
+}
+
+// APIError 表示 Vertex 返回了 success=false 的业务错误
+type APIError struct {
+	Message string // Vertex 返回的错误信息
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API 业务错误: %s", e.Message)
 }
 
 // Client 是 Vertex SDK 的主要入口点
 type Client struct {
-	BaseURL  string        // Vertex 服务器的基础 URL (例如 "http://127.0.0.1:3000")
-	Req      *resty.Client // 内部使用的 Resty 客户端
-	username string        // 暂存用户名用于初始化登录
-	password string        // 暂存密码用于初始化登录
+	BaseURL string        // Vertex 服务器的基础 URL (例如 "http://127.0.0.1:3000")
+	Req     *resty.Client // 内部使用的 Resty 客户端
+	auth    Authenticator // 认证器，用于初始化登录
+	limits  throttle      // 客户端限流与并发控制
+	retry   RetryPolicy   // 请求重试策略
+
+	middlewares []Middleware  // 请求中间件链
+	logger      *slog.Logger  // 结构化日志，nil 表示不输出
+	logLevel    slog.Level    // 成功请求的日志级别
+	cache       responseCache // 响应缓存，默认不缓存
 }
 
 // ClientOption 是用于配置 Client 的函数选项模式
@@ -35,12 +65,21 @@ type ClientOption func(*Client) error
 
 // WithAuth 提供用户名、密码和可选的初始 Cookie (原始字符串格式)。
 // 如果提供了 Cookie，SDK 会优先尝试使用它进行认证；如果无效或未提供，则自动切换到账号密码登录。
+// 同时提供时等价于 WithAuthenticator(FirstOfAuth(CookieAuth(cookies), PasswordAuth(username, password)))。
+// 只提供 Cookie 时不校验其有效性，Cookie 已失效也会返回可用的 Client (之后的请求返回认证错误)；
+// 需要在创建时校验请使用 WithAuthenticator(CookieAuth(cookies))。
 func WithAuth(username, password, cookies string) ClientOption {
 	return func(c *Client) error {
-		c.username = username
-		c.password = password
-		if cookies != "" {
-			return c.SetCookies(cookies)
+		hasPassword := username != "" && password != ""
+		switch {
+		case cookies != "" && hasPassword:
+			c.auth = FirstOfAuth(CookieAuth(cookies), PasswordAuth(username, password))
+		case cookies != "":
+			c.auth = AuthenticatorFunc(func(ctx context.Context, c *Client) error {
+				return c.SetCookies(cookies)
+			})
+		case hasPassword:
+			c.auth = PasswordAuth(username, password)
 		}
 		return nil
 	}
@@ -54,7 +93,8 @@ func WithTimeout(d time.Duration) ClientOption {
 	}
 }
 
-// WithDebug 开启或关闭详细调试日志
+// WithDebug 开启或关闭 resty 的原始调试输出。
+// 注意：原始输出会打印 Cookie 与密码哈希，生产环境请改用 WithLogger。
 func WithDebug(enabled bool) ClientOption {
 	return func(c *Client) error {
 		c.Req.SetDebug(enabled)
@@ -70,10 +110,7 @@ func NewClient(ctx context.Context, host string, opts ...ClientOption) (*Client,
 	restyClient := resty.New()
 	restyClient.SetBaseURL(host)
 
-	// 默认重试与超时配置
-	restyClient.SetRetryCount(3)
-	restyClient.SetRetryWaitTime(200 * time.Millisecond)
-	restyClient.SetRetryMaxWaitTime(3 * time.Second)
+	// 默认超时配置 (重试由 Client.retry 控制，不使用 resty 内置重试)
 	restyClient.SetTimeout(10 * time.Second)
 
 	// 初始化 Cookie 管理
@@ -84,8 +121,10 @@ func NewClient(ctx context.Context, host string, opts ...ClientOption) (*Client,
 	restyClient.SetCookieJar(jar)
 
 	c := &Client{
-		BaseURL: host,
-		Req:     restyClient,
+		BaseURL:  host,
+		Req:      restyClient,
+		retry:    DefaultRetryPolicy(),
+		logLevel: slog.LevelDebug,
 	}
 
 	// 应用所有配置选项
@@ -95,22 +134,9 @@ func NewClient(ctx context.Context, host string, opts ...ClientOption) (*Client,
 		}
 	}
 
-	// 自动登录验证逻辑：
-	// 1. 如果已有 Cookie，验证其有效性 (通过调用 /api/user/get 接口检测)
-	u, _ := url.Parse(host)
-	loggedIn := false
-	if len(restyClient.GetClient().Jar.Cookies(u)) > 0 {
-		_, err := c.request(ctx, "GET", "/api/user/get", nil, nil)
-		if err == nil {
-			loggedIn = true
-		}
-	}
-
-	// 2. 如果 Cookie 无效且提供了账号密码，执行自动登录
-	if !loggedIn && c.username != "" && c.password != "" {
-		if err := c.Login(ctx, c.username, c.password); err != nil {
-			return nil, fmt.Errorf("认证失败: %w", err)
-		}
+	// 执行认证 (如 WithAuth: 先验证 Cookie，无效时使用账号密码登录)
+	if err := c.Authenticate(ctx); err != nil {
+		return nil, fmt.Errorf("认证失败: %w", err)
 	}
 
 	return c, nil
@@ -120,8 +146,11 @@ func NewClient(ctx context.Context, host string, opts ...ClientOption) (*Client,
 func (c *Client) Login(ctx context.Context, username, password string) error {
 	hasher := md5.New()
 	hasher.Write([]byte(password))
-	md5Password := hex.EncodeToString(hasher.Sum(nil))
+	return c.LoginHashed(ctx, username, hex.EncodeToString(hasher.Sum(nil)))
+}
 
+// LoginHashed 使用已经过 MD5 处理的密码 (32 位十六进制) 执行登录
+func (c *Client) LoginHashed(ctx context.Context, username, md5Password string) error {
 	payload := map[string]string{
 		"username": username,
 		"password": md5Password,
@@ -170,32 +199,118 @@ func (c *Client) SetCookies(cookieStr string) error {
 // 辅助方法 Helpers
 // ==========================================
 
-// request 是内部通用的 HTTP 请求封装
+// request 是内部通用的 HTTP 请求封装，依次经过响应缓存、中间件链、重试与限流。
+// 命中缓存 (或与并发的相同请求共用结果) 时同样经过中间件，此时 Request.CacheHit 为 true，且不会发起 HTTP 请求。
 func (c *Client) request(ctx context.Context, method, path string, params map[string]string, body interface{}) (*Response, error) {
+	req := &Request{
+		Operation: operationName(method, path),
+		Method:    method,
+		Path:      path,
+		Params:    params,
+		Body:      body,
+		Header:    http.Header{},
+	}
+	if ttl := c.cache.ttl(method, path); ttl > 0 {
+		resp, shared, err := c.cache.do(ctx, path, params, ttl, func() (*Response, error) {
+			return c.dispatch(ctx, req, c.execute)
+		})
+		if !shared {
+			return resp, err
+		}
+		req.CacheHit = true
+		return c.dispatch(ctx, req, func(context.Context, *Request) (*Response, error) {
+			return resp, err
+		})
+	}
+	resp, err := c.dispatch(ctx, req, c.execute)
+	if err == nil {
+		c.cache.invalidateAfter(method, path)
+	}
+	return resp, err
+}
+
+// dispatch 将请求交给中间件链，final 为链末端的处理函数
+func (c *Client) dispatch(ctx context.Context, req *Request, final Handler) (*Response, error) {
+	handler := final
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	return handler(ctx, req)
+}
+
+// execute 按重试策略重复执行单次请求
+func (c *Client) execute(ctx context.Context, req *Request) (*Response, error) {
+	retryable := c.retry.allows(req.Method, req.Path)
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err := c.do(ctx, req)
+		if err == nil || !retryable || attempt >= c.retry.MaxRetries || !c.retry.retryable(err) {
+			c.logAttempt(ctx, req, attempt, resp, err, time.Since(start), false)
+			return resp, err
+		}
+
+		wait, ok := c.retry.wait(attempt, err)
+		c.logAttempt(ctx, req, attempt, resp, err, time.Since(start), ok)
+		if !ok {
+			return nil, err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			// 返回 ctx 的错误以便调用方通过 errors.Is 判断取消或超时，同时保留上一次请求的错误
+			timer.Stop()
+			return nil, fmt.Errorf("等待重试时请求被取消: %w (上次错误: %w)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// do 执行单次 HTTP 请求 (含限流)
+func (c *Client) do(ctx context.Context, req *Request) (*Response, error) {
+	release, err := c.limits.acquire(ctx, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	var apiResp Response
-	req := c.Req.R().SetContext(ctx).SetResult(&apiResp)
+	r := c.Req.R().SetContext(ctx).SetResult(&apiResp)
+
+	if req.Params != nil {
+		r.SetQueryParams(req.Params)
+	}
 
-	if params != nil {
-		req.SetQueryParams(params)
+	if len(req.Header) > 0 {
+		r.SetHeaderMultiValues(req.Header)
 	}
 
-	if body != nil {
-		req.SetBody(body)
+	if mp, ok := req.Body.(*Multipart); ok {
+		r.SetMultipartFormData(mp.Fields)
+		for _, f := range mp.Files {
+			r.SetFileReader(f.Field, f.Name, bytes.NewReader(f.Data))
+		}
+	} else if req.Body != nil {
+		r.SetBody(req.Body)
 	}
 
-	resp, err := req.Execute(method, path)
+	resp, err := r.Execute(req.Method, req.Path)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.IsError() {
-		return nil, fmt.Errorf("HTTP 错误: %d %s", resp.StatusCode(), resp.Status())
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode(),
+			Status:     resp.Status(),
+			RetryAfter: parseRetryAfter(resp.Header().Get("Retry-After")),
+		}
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("API 业务错误: %s", apiResp.Message)
+		return nil, &APIError{Message: apiResp.Message}
 	}
 
+	apiResp.StatusCode = resp.StatusCode()
 	return &apiResp, nil
 }
 
@@ -215,12 +330,12 @@ func (c *Client) post(ctx context.Context, path string, body interface{}) (*Resp
 
 // Server 代表 Vertex 管理的服务器信息
 type Server struct {
-	ID       string `json:"id"`
+	ID       string `json:"id,omitempty"`
 	Alias    string `json:"alias"`    // 别名
 	Host     string `json:"host"`     // 地址
 	Port     int    `json:"port"`     // 端口
 	User     string `json:"user"`     // 用户名
-	Password string `json:"password"` // 密码
+	Password Secret `json:"password"` // 密码 (输出时脱敏，Reveal 获取原文)
 	Enable   bool   `json:"enable"`   // 是否启用
 	Status   bool   `json:"status"`   // 状态
 	Used     bool   `json:"used"`     // 是否已使用
@@ -240,6 +355,31 @@ func (c *Client) ListServers(ctx context.Context) ([]Server, error) {
 	return servers, nil
 }
 
+// AddServer 添加服务器
+func (c *Client) AddServer(ctx context.Context, server Server) error {
+	if server.Password.IsRedacted() {
+		return fmt.Errorf("服务器 %s: %w", server.Alias, ErrRedactedSecret)
+	}
+	_, err := c.post(ctx, "/api/server/add", server.revealed())
+	return err
+}
+
+// ModifyServer 修改服务器
+func (c *Client) ModifyServer(ctx context.Context, server Server) error {
+	if server.Password.IsRedacted() {
+		return fmt.Errorf("服务器 %s: %w", server.Alias, ErrRedactedSecret)
+	}
+	_, err := c.post(ctx, "/api/server/modify", server.revealed())
+	return err
+}
+
+// DeleteServer 删除服务器
+func (c *Client) DeleteServer(ctx context.Context, id string) error {
+	payload := map[string]string{"id": id}
+	_, err := c.post(ctx, "/api/server/delete", payload)
+	return err
+}
+
 // GetServerNetSpeed 获取服务器实时网速数据
 func (c *Client) GetServerNetSpeed(ctx context.Context) (map[string]interface{}, error) {
 	resp, err := c.get(ctx, "/api/server/netSpeed", nil)
@@ -330,7 +470,7 @@ type DownloaderConfig struct {
 	Type                  string   `json:"type"`           // 类型 (必填)
 	ClientURL             string   `json:"clientUrl"`      // 地址 (必填)
 	Username              string   `json:"username"`       // 用户名 (必填)
-	Password              string   `json:"password"`       // 密码 (必填)
+	Password              Secret   `json:"password"`       // 密码 (必填，输出时脱敏)
 	Enable                bool     `json:"enable"`
 	PushNotify            bool     `json:"pushNotify"`                      // 启用推送通知
 	Notify                string   `json:"notify,omitempty"`                // 通知方式 ID
@@ -428,13 +568,19 @@ func (c *Client) FindDownloadersByAlias(ctx context.Context, searchKey string) (
 
 // AddDownloader 添加下载器
 func (c *Client) AddDownloader(ctx context.Context, cfg DownloaderConfig) error {
-	_, err := c.post(ctx, "/api/downloader/add", cfg)
+	if cfg.Password.IsRedacted() {
+		return fmt.Errorf("下载器 %s: %w", cfg.Alias, ErrRedactedSecret)
+	}
+	_, err := c.post(ctx, "/api/downloader/add", cfg.revealed())
 	return err
 }
 
 // ModifyDownloader 修改下载器配置
 func (c *Client) ModifyDownloader(ctx context.Context, cfg DownloaderConfig) error {
-	_, err := c.post(ctx, "/api/downloader/modify", cfg)
+	if cfg.Password.IsRedacted() {
+		return fmt.Errorf("下载器 %s: %w", cfg.Alias, ErrRedactedSecret)
+	}
+	_, err := c.post(ctx, "/api/downloader/modify", cfg.revealed())
 	return err
 }
 
@@ -707,17 +853,32 @@ func (c *Client) ListRssHistory(ctx context.Context, page, length int, rssID str
 // 种子管理 API (Torrent)
 // ==========================================
 
-// Torrent 种子基础信息
+// Torrent 种子信息。服务端新增的未知字段保存在 Extra 中，重新序列化时会原样输出。
 type Torrent struct {
-	Hash          string  `json:"hash"`
-	Name          string  `json:"name"`
-	Size          int64   `json:"size"`
-	Progress      float64 `json:"progress"`      // 进度 (0-1)
-	UploadSpeed   int64   `json:"uploadSpeed"`   // 上传速度 (B/s)
-	DownloadSpeed int64   `json:"downloadSpeed"` // 下载速度 (B/s)
-	State         string  `json:"state"`         // 状态 (如 seeding, downloading)
-	ClientAlias   string  `json:"clientAlias"`   // 所属下载器别名
-	Link          string  `json:"link,omitempty"`
+	Hash          string       `json:"hash"`
+	Name          string       `json:"name"`
+	Size          int64        `json:"size"`
+	Progress      float64      `json:"progress"`      // 进度 (0-1)
+	UploadSpeed   int64        `json:"uploadSpeed"`   // 上传速度 (B/s)
+	DownloadSpeed int64        `json:"downloadSpeed"` // 下载速度 (B/s)
+	State         TorrentState `json:"state"`         // 状态 (如 seeding, downloading)
+	ClientAlias   string       `json:"clientAlias"`   // 所属下载器别名
+	ClientID      string       `json:"clientId"`      // 所属下载器 ID
+	Link          string       `json:"link,omitempty"`
+
+	Category      string   `json:"category"`      // 分类
+	Tags          []string `json:"tags"`          // 标签 (兼容逗号分隔的字符串)
+	Tracker       string   `json:"tracker"`       // 当前 Tracker
+	SavePath      string   `json:"savePath"`      // 保存路径
+	Ratio         float64  `json:"ratio"`         // 分享率
+	Uploaded      int64    `json:"uploaded"`      // 已上传 (字节)
+	Downloaded    int64    `json:"downloaded"`    // 已下载 (字节)
+	AddedTime     int64    `json:"addedTime"`     // 添加时间 (Unix 秒)
+	CompletedTime int64    `json:"completedTime"` // 完成时间 (Unix 秒)，未完成时为 0 或负数
+	Seeders       int      `json:"seeder"`        // 做种数
+	Leechers      int      `json:"leecher"`       // 下载数
+
+	Extra map[string]json.RawMessage `json:"-"` // 未识别的字段
 }
 
 // TorrentListOption 种子列表查询选项
@@ -779,6 +940,39 @@ func (c *Client) ListTorrents(ctx context.Context, opt TorrentListOption) (*Torr
 	return &res, nil
 }
 
+// ListAllTorrents 自动翻页获取全部种子 (opt 中的 Page 会被忽略，Length 作为每页数量，默认 1000)
+func (c *Client) ListAllTorrents(ctx context.Context, opt TorrentListOption) ([]Torrent, error) {
+	if opt.Length <= 0 {
+		opt.Length = 1000
+	}
+	if len(opt.ClientList) == 0 {
+		// 只解析一次下载器列表，避免每页重复请求
+		downloaders, err := c.ListDownloaders(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range downloaders {
+			opt.ClientList = append(opt.ClientList, d.ID)
+		}
+		if len(opt.ClientList) == 0 {
+			return nil, nil
+		}
+	}
+
+	var all []Torrent
+	for page := 1; ; page++ {
+		opt.Page = page
+		res, err := c.ListTorrents(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, res.Torrents...)
+		if len(res.Torrents) == 0 || len(all) >= res.Total {
+			return all, nil
+		}
+	}
+}
+
 // GetTorrentInfo 获取指定 Hash 的种子详情
 func (c *Client) GetTorrentInfo(ctx context.Context, hash string) (*Torrent, error) {
 	resp, err := c.get(ctx, "/api/torrent/info", map[string]string{"hash": hash})
@@ -798,13 +992,30 @@ func (c *Client) LinkTorrent(ctx context.Context, payload interface{}) error {
 	return err
 }
 
-// DeleteTorrent 删除种子
+// DeleteTorrent 删除种子任务，保留磁盘上的数据文件。
+// deleteFiles 仅为兼容旧版本签名而保留，不会删除任何文件；需要一并删除数据时使用 DeleteTorrentAndFiles。
 func (c *Client) DeleteTorrent(ctx context.Context, hash, clientId string, deleteFiles bool) error {
+	return c.deleteTorrent(ctx, hash, clientId, []TorrentFile{})
+}
+
+// DeleteTorrentAndFiles 删除种子任务，并删除其在磁盘上的全部数据文件 (不可恢复)。
+// 会先通过 GetTorrentFiles 获取文件列表；列表为空时返回错误且不做任何删除，避免只删除任务而残留数据。
+func (c *Client) DeleteTorrentAndFiles(ctx context.Context, hash, clientId string) error {
+	files, err := c.GetTorrentFiles(ctx, hash, clientId)
+	if err != nil {
+		return fmt.Errorf("获取种子文件列表失败: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("种子 %s 的文件列表为空，未执行删除", hash)
+	}
+	return c.deleteTorrent(ctx, hash, clientId, files)
+}
+
+func (c *Client) deleteTorrent(ctx context.Context, hash, clientId string, files []TorrentFile) error {
 	payload := map[string]interface{}{
 		"hash":     hash,
 		"clientId": clientId,
-		// 如果需要删除文件，需要传递 files 数组，这里简化为只删除种子
-		"files": []interface{}{},
+		"files":    files,
 	}
 	_, err := c.post(ctx, "/api/torrent/deleteTorrent", payload)
 	return err
@@ -0,0 +1,343 @@
+package vertex
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ==========================================
+// Vnstat 流量序列 (Vnstat Series)
+// ==========================================
+
+// VnstatGranularity Vnstat 统计粒度
+type VnstatGranularity string
+
+const (
+	VnstatFiveMinute VnstatGranularity = "fiveminute" // 5 分钟
+	VnstatHour       VnstatGranularity = "hour"       // 小时
+	VnstatDay        VnstatGranularity = "day"        // 天
+	VnstatMonth      VnstatGranularity = "month"      // 月
+)
+
+// VnstatPoint 一个统计区间的流量，Time 为区间起点
+type VnstatPoint struct {
+	Time time.Time
+	Rx   int64 // 接收 (下行) 字节数
+	Tx   int64 // 发送 (上行) 字节数
+}
+
+// Total 上下行合计
+func (p VnstatPoint) Total() int64 {
+	return p.Rx + p.Tx
+}
+
+// VnstatSeries 按时间升序排列的流量序列
+type VnstatSeries []VnstatPoint
+
+// Series 将指定粒度的原始数据解析为流量序列。
+// 时间按服务器本地时区 (time.Local) 解析，无法识别的条目会被忽略。
+func (v *VnstatInfo) Series(g VnstatGranularity) VnstatSeries {
+	var raw map[string]interface{}
+	switch g {
+	case VnstatFiveMinute:
+		raw = v.FiveMinute
+	case VnstatHour:
+		raw = v.Hour
+	case VnstatDay:
+		raw = v.Day
+	case VnstatMonth:
+		raw = v.Month
+	}
+
+	var s VnstatSeries
+	for key, item := range raw {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := VnstatPoint{
+			Rx: vnstatBytes(obj, "rx", "down", "download"),
+			Tx: vnstatBytes(obj, "tx", "up", "upload"),
+		}
+		if p.Time, ok = vnstatTime(key, obj); !ok {
+			continue
+		}
+		s = append(s, p)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Time.Before(s[j].Time) })
+	return s
+}
+
+// Between 返回起点落在 [from, to) 内的统计区间
+func (s VnstatSeries) Between(from, to time.Time) VnstatSeries {
+	var out VnstatSeries
+	for _, p := range s {
+		if !p.Time.Before(from) && p.Time.Before(to) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Sum 返回全部区间的上下行合计
+func (s VnstatSeries) Sum() (rx, tx int64) {
+	for _, p := range s {
+		rx += p.Rx
+		tx += p.Tx
+	}
+	return rx, tx
+}
+
+// SumBetween 返回起点落在 [from, to) 内的区间的上下行合计。
+// 区间按整体计入，窗口精度取决于序列的粒度。
+func (s VnstatSeries) SumBetween(from, to time.Time) (rx, tx int64) {
+	return s.Between(from, to).Sum()
+}
+
+// Last 返回最后 (最新) 一个区间
+func (s VnstatSeries) Last() (VnstatPoint, bool) {
+	if len(s) == 0 {
+		return VnstatPoint{}, false
+	}
+	return s[len(s)-1], true
+}
+
+// vnstatBytes 按候选字段名读取字节数
+func vnstatBytes(obj map[string]interface{}, keys ...string) int64 {
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case float64:
+			return int64(v)
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// vnstatTimeLayouts 键或 time 字段可能使用的时间格式
+var vnstatTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02",
+	"2006-01",
+}
+
+// vnstatTime 解析区间起点，支持 vnstat --json 的 date/time 对象、Unix 时间戳以及常见的日期字符串
+func vnstatTime(key string, obj map[string]interface{}) (time.Time, bool) {
+	if date, ok := obj["date"].(map[string]interface{}); ok {
+		year, month, day := vnstatInt(date["year"]), vnstatInt(date["month"]), vnstatInt(date["day"])
+		if day == 0 {
+			day = 1
+		}
+		var hour, minute int
+		if t, ok := obj["time"].(map[string]interface{}); ok {
+			hour, minute = vnstatInt(t["hour"]), vnstatInt(t["minute"])
+		}
+		if year > 0 && month > 0 {
+			return time.Date(year, time.Month(month), day, hour, minute, 0, 0, time.Local), true
+		}
+	}
+	for _, k := range []string{"timestamp", "time", "date"} {
+		switch v := obj[k].(type) {
+		case float64:
+			return vnstatUnix(int64(v)), true
+		case string:
+			if t, ok := parseVnstatTime(v); ok {
+				return t, true
+			}
+		}
+	}
+	return parseVnstatTime(key)
+}
+
+func parseVnstatTime(s string) (time.Time, bool) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return vnstatUnix(n), true
+	}
+	for _, layout := range vnstatTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// vnstatUnix 兼容秒与毫秒时间戳
+func vnstatUnix(n int64) time.Time {
+	if n > 1e12 {
+		return time.UnixMilli(n)
+	}
+	return time.Unix(n, 0)
+}
+
+func vnstatInt(v interface{}) int {
+	if f, ok := v.(float64); ok {
+		return int(f)
+	}
+	return 0
+}
+
+// ==========================================
+// 流量配额 (Traffic Quota)
+// ==========================================
+
+// QuotaDirection 配额的计费方向
+type QuotaDirection int
+
+const (
+	QuotaTotal QuotaDirection = iota // 上下行合计 (默认)
+	QuotaTx                          // 仅上行
+	QuotaRx                          // 仅下行
+	QuotaMax                         // 上下行取较大者
+)
+
+// TrafficQuota 单台服务器的月度流量配额
+type TrafficQuota struct {
+	ServerID   string
+	MonthlyCap int64          // 每个计费周期的流量上限 (字节)
+	BillingDay int            // 每月账单日 (1-31，超过当月天数时按月末计算)，默认 1
+	Direction  QuotaDirection // 计费方向
+}
+
+// QuotaStatus 配额的当前使用情况
+type QuotaStatus struct {
+	ServerID    string
+	PeriodStart time.Time // 当前计费周期开始
+	PeriodEnd   time.Time // 当前计费周期结束 (下一个账单日)
+	Rx          int64     // 本周期下行
+	Tx          int64     // 本周期上行
+	Used        int64     // 按计费方向统计的已用流量
+	Projected   int64     // 按当前速率推算的周期末用量
+	Cap         int64
+	Err         error // 获取流量失败时的错误
+}
+
+// UsedRatio 已用流量占上限的比例
+func (s QuotaStatus) UsedRatio() float64 {
+	if s.Cap <= 0 {
+		return 0
+	}
+	return float64(s.Used) / float64(s.Cap)
+}
+
+// Exceeded 是否已超出上限
+func (s QuotaStatus) Exceeded() bool {
+	return s.Cap > 0 && s.Used > s.Cap
+}
+
+// WillExceed 按当前速率周期末是否会超出上限
+func (s QuotaStatus) WillExceed() bool {
+	return s.Cap > 0 && s.Projected > s.Cap
+}
+
+// BillingPeriod 返回 now 所在的计费周期 [start, end)
+func (q TrafficQuota) BillingPeriod(now time.Time) (start, end time.Time) {
+	billingDate := func(year int, month time.Month) time.Time {
+		day := q.BillingDay
+		if day < 1 {
+			day = 1
+		}
+		// 下个月第 0 天即本月最后一天
+		if last := time.Date(year, month+1, 0, 0, 0, 0, 0, now.Location()).Day(); day > last {
+			day = last
+		}
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	}
+	start = billingDate(now.Year(), now.Month())
+	if now.Before(start) {
+		prev := start.AddDate(0, 0, -start.Day()) // 上个月最后一天
+		start = billingDate(prev.Year(), prev.Month())
+	}
+	next := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, now.Location())
+	return start, billingDate(next.Year(), next.Month())
+}
+
+// Evaluate 根据 Vnstat 数据计算 now 所在计费周期的用量与推算值。
+// 优先使用按天统计，缺失时退回按小时统计。
+func (q TrafficQuota) Evaluate(info *VnstatInfo, now time.Time) QuotaStatus {
+	start, end := q.BillingPeriod(now)
+	st := QuotaStatus{ServerID: q.ServerID, PeriodStart: start, PeriodEnd: end, Cap: q.MonthlyCap}
+
+	series := info.Series(VnstatDay)
+	if len(series) == 0 {
+		series = info.Series(VnstatHour)
+	}
+	st.Rx, st.Tx = series.SumBetween(start, end)
+	st.Used = q.count(st.Rx, st.Tx)
+
+	// 周期刚开始时样本太少，至少按 1 小时计算速率
+	elapsed := now.Sub(start)
+	if elapsed < time.Hour {
+		elapsed = time.Hour
+	}
+	st.Projected = int64(float64(st.Used) * float64(end.Sub(start)) / float64(elapsed))
+	if st.Projected < st.Used {
+		st.Projected = st.Used
+	}
+	return st
+}
+
+func (q TrafficQuota) count(rx, tx int64) int64 {
+	switch q.Direction {
+	case QuotaTx:
+		return tx
+	case QuotaRx:
+		return rx
+	case QuotaMax:
+		if rx > tx {
+			return rx
+		}
+		return tx
+	default:
+		return rx + tx
+	}
+}
+
+// QuotaTracker 跟踪多台服务器的流量配额
+type QuotaTracker struct {
+	client *Client
+	quotas []TrafficQuota
+	now    func() time.Time
+}
+
+// NewQuotaTracker 创建流量配额跟踪器
+func NewQuotaTracker(c *Client, quotas ...TrafficQuota) *QuotaTracker {
+	return &QuotaTracker{client: c, quotas: quotas, now: time.Now}
+}
+
+// Check 查询全部服务器的 Vnstat 并计算配额状态，单台服务器失败时记录在 QuotaStatus.Err 中
+func (t *QuotaTracker) Check(ctx context.Context) []QuotaStatus {
+	ids := make([]string, len(t.quotas))
+	for i, q := range t.quotas {
+		ids[i] = q.ServerID
+	}
+	vnstat := t.client.GetServersVnstat(ctx, ids)
+
+	out := make([]QuotaStatus, len(t.quotas))
+	for i, q := range t.quotas {
+		if err, failed := vnstat.Errors[q.ServerID]; failed {
+			out[i] = QuotaStatus{ServerID: q.ServerID, Cap: q.MonthlyCap, Err: fmt.Errorf("获取服务器 %s 的 Vnstat 失败: %w", q.ServerID, err)}
+			continue
+		}
+		out[i] = q.Evaluate(vnstat.Values[q.ServerID], t.now())
+	}
+	return out
+}
+
+// AtRisk 返回已超出或预计将超出上限的服务器
+func (t *QuotaTracker) AtRisk(ctx context.Context) []QuotaStatus {
+	var out []QuotaStatus
+	for _, st := range t.Check(ctx) {
+		if st.Err == nil && st.WillExceed() {
+			out = append(out, st)
+		}
+	}
+	return out
+}
@@ -0,0 +1,279 @@
+package vertex
+
+import (
+	"context"
+	"time"
+)
+
+// ==========================================
+// 状态监听 (Watcher)
+// ==========================================
+
+// EventType 监听事件类型
+type EventType string
+
+const (
+	EventTorrentAdded            EventType = "torrentAdded"            // 新增种子
+	EventTorrentStateChanged     EventType = "torrentStateChanged"     // 种子状态变化 (如 downloading -> seeding)
+	EventTorrentRemoved          EventType = "torrentRemoved"          // 种子被删除
+	EventDownloaderStatusChanged EventType = "downloaderStatusChanged" // 下载器在线状态变化或做种数下降
+	EventSpeedThresholdCrossed   EventType = "speedThresholdCrossed"   // 下载器速度越过阈值
+	EventError                   EventType = "error"                   // 轮询出错 (之后会按退避策略重试)
+)
+
+// SpeedDirection 速度阈值的方向
+type SpeedDirection string
+
+const (
+	SpeedUpload   SpeedDirection = "upload"   // 上传速度
+	SpeedDownload SpeedDirection = "download" // 下载速度
+)
+
+// SpeedThreshold 下载器速度阈值，速度从一侧越过到另一侧时触发 EventSpeedThresholdCrossed
+type SpeedThreshold struct {
+	DownloaderID   string         // 下载器 ID，为空表示所有下载器
+	Direction      SpeedDirection // 上传或下载
+	BytesPerSecond float64        // 阈值 (B/s)
+}
+
+// Event 监听事件
+type Event struct {
+	Type           EventType
+	Time           time.Time       // 事件产生时间
+	Torrent        *Torrent        // 当前种子 (TorrentRemoved 时为最后一次快照)
+	PrevTorrent    *Torrent        // 变化前的种子 (仅 TorrentStateChanged)
+	Downloader     *DownloaderInfo // 当前下载器
+	PrevDownloader *DownloaderInfo // 变化前的下载器
+	Threshold      *SpeedThreshold // 被越过的阈值 (仅 SpeedThresholdCrossed)
+	Above          bool            // 越过后是否高于阈值 (仅 SpeedThresholdCrossed)
+	Err            error           // 错误信息 (仅 Error)
+}
+
+// Watcher 定期轮询 ListTorrents / ListDownloaders，对比快照后产生类型化事件
+type Watcher struct {
+	client     *Client
+	interval   time.Duration
+	maxBackoff time.Duration
+	buffer     int
+	torrents   bool
+	listOpt    TorrentListOption
+	thresholds []SpeedThreshold
+}
+
+// WatcherOption 是用于配置 Watcher 的函数选项模式
+type WatcherOption func(*Watcher)
+
+// WithWatchInterval 配置轮询间隔 (默认 30 秒)，非正数会被忽略
+func WithWatchInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.interval = d
+		}
+	}
+}
+
+// WithWatchMaxBackoff 配置出错时退避等待的上限 (默认 5 分钟)，非正数会被忽略
+func WithWatchMaxBackoff(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.maxBackoff = d
+		}
+	}
+}
+
+// WithWatchBuffer 配置事件通道的缓冲大小 (默认 64)，非正数会被忽略
+func WithWatchBuffer(n int) WatcherOption {
+	return func(w *Watcher) {
+		if n > 0 {
+			w.buffer = n
+		}
+	}
+}
+
+// WithWatchTorrents 配置是否监听种子变化 (默认开启)，opt 用于限定下载器范围等查询条件
+func WithWatchTorrents(enabled bool, opt TorrentListOption) WatcherOption {
+	return func(w *Watcher) {
+		w.torrents = enabled
+		w.listOpt = opt
+	}
+}
+
+// WithSpeedThreshold 添加一个下载器速度阈值
+func WithSpeedThreshold(t SpeedThreshold) WatcherOption {
+	return func(w *Watcher) {
+		w.thresholds = append(w.thresholds, t)
+	}
+}
+
+// NewWatcher 创建一个状态监听器
+func NewWatcher(c *Client, opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		client:     c,
+		interval:   30 * time.Second,
+		maxBackoff: 5 * time.Minute,
+		buffer:     64,
+		torrents:   true,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// watchSnapshot 一次轮询得到的快照
+type watchSnapshot struct {
+	torrents    map[string]Torrent
+	downloaders map[string]DownloaderInfo
+}
+
+// Watch 启动后台轮询并返回事件通道。
+// 首次轮询只建立基线快照，不产生事件；ctx 结束后通道会被关闭。
+func (w *Watcher) Watch(ctx context.Context) <-chan Event {
+	events := make(chan Event, w.buffer)
+	go func() {
+		defer close(events)
+
+		var prev *watchSnapshot
+		failures := 0
+		for {
+			cur, err := w.poll(ctx)
+			wait := w.interval
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				failures++
+				wait = w.backoff(failures)
+				if !w.emit(ctx, events, Event{Type: EventError, Time: time.Now(), Err: err}) {
+					return
+				}
+			} else {
+				failures = 0
+				if prev != nil {
+					for _, ev := range w.diff(prev, cur) {
+						if !w.emit(ctx, events, ev) {
+							return
+						}
+					}
+				}
+				prev = cur
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return events
+}
+
+// emit 发送事件，ctx 结束时返回 false
+func (w *Watcher) emit(ctx context.Context, events chan<- Event, ev Event) bool {
+	select {
+	case events <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// backoff 计算第 n 次连续失败后的等待时间 (指数增长，不超过 maxBackoff)
+func (w *Watcher) backoff(n int) time.Duration {
+	d := w.interval
+	for i := 0; i < n && d < w.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > w.maxBackoff {
+		d = w.maxBackoff
+	}
+	return d
+}
+
+// poll 拉取一次完整快照
+func (w *Watcher) poll(ctx context.Context) (*watchSnapshot, error) {
+	snap := &watchSnapshot{
+		torrents:    make(map[string]Torrent),
+		downloaders: make(map[string]DownloaderInfo),
+	}
+
+	downloaders, err := w.client.ListDownloaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range downloaders {
+		snap.downloaders[d.ID] = d
+	}
+
+	if w.torrents {
+		opt := w.listOpt
+		if len(opt.ClientList) == 0 {
+			// 复用本次已获取的下载器列表，避免 ListAllTorrents 再请求一次
+			if len(downloaders) == 0 {
+				return snap, nil
+			}
+			for _, d := range downloaders {
+				opt.ClientList = append(opt.ClientList, d.ID)
+			}
+		}
+		torrents, err := w.client.ListAllTorrents(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range torrents {
+			// 同一个种子可能同时存在于多个下载器，因此以 下载器ID/Hash 作为键 (别名可修改，不能作为键)
+			snap.torrents[t.ClientID+"/"+t.Hash] = t
+		}
+	}
+	return snap, nil
+}
+
+// diff 对比两次快照并生成事件
+func (w *Watcher) diff(prev, cur *watchSnapshot) []Event {
+	now := time.Now()
+	var events []Event
+
+	for key, t := range cur.torrents {
+		old, ok := prev.torrents[key]
+		if !ok {
+			events = append(events, Event{Type: EventTorrentAdded, Time: now, Torrent: &t})
+			continue
+		}
+		if old.State != t.State {
+			events = append(events, Event{Type: EventTorrentStateChanged, Time: now, Torrent: &t, PrevTorrent: &old})
+		}
+	}
+	for key, t := range prev.torrents {
+		if _, ok := cur.torrents[key]; !ok {
+			events = append(events, Event{Type: EventTorrentRemoved, Time: now, Torrent: &t})
+		}
+	}
+
+	for id, d := range cur.downloaders {
+		old, ok := prev.downloaders[id]
+		if !ok {
+			continue
+		}
+		if old.Status != d.Status || d.SeedingCount < old.SeedingCount {
+			events = append(events, Event{Type: EventDownloaderStatusChanged, Time: now, Downloader: &d, PrevDownloader: &old})
+		}
+		// th 是阈值的副本，事件中的 Threshold 不会指向 Watcher 内部的配置
+		for _, th := range w.thresholds {
+			if th.DownloaderID != "" && th.DownloaderID != id {
+				continue
+			}
+			before, after := old.UploadSpeed, d.UploadSpeed
+			if th.Direction == SpeedDownload {
+				before, after = old.DownloadSpeed, d.DownloadSpeed
+			}
+			wasAbove, isAbove := before >= th.BytesPerSecond, after >= th.BytesPerSecond
+			if wasAbove != isAbove {
+				events = append(events, Event{Type: EventSpeedThresholdCrossed, Time: now, Downloader: &d, PrevDownloader: &old, Threshold: &th, Above: isAbove})
+			}
+		}
+	}
+	return events
+}
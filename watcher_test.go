@@ -0,0 +1,234 @@
+package vertex
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func snapshot(torrents []Torrent, downloaders []DownloaderInfo) *watchSnapshot {
+	s := &watchSnapshot{torrents: make(map[string]Torrent), downloaders: make(map[string]DownloaderInfo)}
+	for _, t := range torrents {
+		s.torrents[t.ClientID+"/"+t.Hash] = t
+	}
+	for _, d := range downloaders {
+		s.downloaders[d.ID] = d
+	}
+	return s
+}
+
+func eventTypes(events []Event) []string {
+	var out []string
+	for _, ev := range events {
+		name := string(ev.Type)
+		if ev.Torrent != nil {
+			name += ":" + ev.Torrent.Hash
+		}
+		if ev.Downloader != nil {
+			name += ":" + ev.Downloader.ID
+		}
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestWatcherDiff(t *testing.T) {
+	qb := func(status bool, seeding int, up float64) DownloaderInfo {
+		d := DownloaderInfo{Status: status, SeedingCount: seeding, UploadSpeed: up}
+		d.ID = "qb"
+		return d
+	}
+	tor := func(hash string, state TorrentState) Torrent {
+		return Torrent{Hash: hash, ClientAlias: "qb", ClientID: "qb", State: state}
+	}
+
+	tests := []struct {
+		name string
+		opts []WatcherOption
+		prev *watchSnapshot
+		cur  *watchSnapshot
+		want []string
+	}{
+		{
+			name: "无变化",
+			prev: snapshot([]Torrent{tor("a", "seeding")}, []DownloaderInfo{qb(true, 1, 0)}),
+			cur:  snapshot([]Torrent{tor("a", "seeding")}, []DownloaderInfo{qb(true, 1, 0)}),
+		},
+		{
+			name: "新增与删除",
+			prev: snapshot([]Torrent{tor("a", "seeding")}, nil),
+			cur:  snapshot([]Torrent{tor("b", "downloading")}, nil),
+			want: []string{"torrentAdded:b", "torrentRemoved:a"},
+		},
+		{
+			name: "状态变化",
+			prev: snapshot([]Torrent{tor("a", "downloading")}, nil),
+			cur:  snapshot([]Torrent{tor("a", "seeding")}, nil),
+			want: []string{"torrentStateChanged:a"},
+		},
+		{
+			name: "同一种子位于不同下载器",
+			prev: snapshot([]Torrent{tor("a", "seeding")}, nil),
+			cur:  snapshot([]Torrent{tor("a", "seeding"), {Hash: "a", ClientAlias: "tr", ClientID: "tr", State: "seeding"}}, nil),
+			want: []string{"torrentAdded:a"},
+		},
+		{
+			name: "下载器改名不产生事件",
+			prev: snapshot([]Torrent{tor("a", "seeding")}, nil),
+			cur:  snapshot([]Torrent{{Hash: "a", ClientAlias: "qb-new", ClientID: "qb", State: "seeding"}}, nil),
+		},
+		{
+			name: "下载器离线",
+			prev: snapshot(nil, []DownloaderInfo{qb(true, 5, 0)}),
+			cur:  snapshot(nil, []DownloaderInfo{qb(false, 5, 0)}),
+			want: []string{"downloaderStatusChanged:qb"},
+		},
+		{
+			name: "做种数下降",
+			prev: snapshot(nil, []DownloaderInfo{qb(true, 5, 0)}),
+			cur:  snapshot(nil, []DownloaderInfo{qb(true, 4, 0)}),
+			want: []string{"downloaderStatusChanged:qb"},
+		},
+		{
+			name: "新出现的下载器不产生事件",
+			prev: snapshot(nil, nil),
+			cur:  snapshot(nil, []DownloaderInfo{qb(true, 5, 0)}),
+		},
+		{
+			name: "越过速度阈值",
+			opts: []WatcherOption{WithSpeedThreshold(SpeedThreshold{Direction: SpeedUpload, BytesPerSecond: 100})},
+			prev: snapshot(nil, []DownloaderInfo{qb(true, 5, 50)}),
+			cur:  snapshot(nil, []DownloaderInfo{qb(true, 5, 150)}),
+			want: []string{"speedThresholdCrossed:qb"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWatcher(nil, tt.opts...)
+			got := eventTypes(w.diff(tt.prev, tt.cur))
+			if len(got) != len(tt.want) {
+				t.Fatalf("事件 = %v, 期望 %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("事件 = %v, 期望 %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWatcherThresholdCopied(t *testing.T) {
+	qb := func(up float64) DownloaderInfo {
+		d := DownloaderInfo{UploadSpeed: up}
+		d.ID = "qb"
+		return d
+	}
+	w := NewWatcher(nil,
+		WithSpeedThreshold(SpeedThreshold{Direction: SpeedUpload, BytesPerSecond: 100}),
+		WithSpeedThreshold(SpeedThreshold{Direction: SpeedUpload, BytesPerSecond: 200}))
+	events := w.diff(snapshot(nil, []DownloaderInfo{qb(50)}), snapshot(nil, []DownloaderInfo{qb(250)}))
+	if len(events) != 2 {
+		t.Fatalf("期望 2 个越过阈值事件，实际 %d 个", len(events))
+	}
+	if events[0].Threshold == events[1].Threshold {
+		t.Fatal("不同事件不应共用同一个阈值")
+	}
+	// 修改事件中的阈值不影响 Watcher 的配置
+	events[0].Threshold.BytesPerSecond = 1
+	events[1].Threshold.BytesPerSecond = 1
+	if w.thresholds[0].BytesPerSecond != 100 || w.thresholds[1].BytesPerSecond != 200 {
+		t.Fatalf("Watcher 的阈值被事件修改: %+v", w.thresholds)
+	}
+}
+
+func TestWatcherIgnoresNonPositiveOptions(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		w := NewWatcher(nil, WithWatchInterval(d), WithWatchMaxBackoff(d))
+		if w.interval != 30*time.Second {
+			t.Errorf("WithWatchInterval(%v) 后 interval = %v", d, w.interval)
+		}
+		if w.maxBackoff != 5*time.Minute {
+			t.Errorf("WithWatchMaxBackoff(%v) 后 maxBackoff = %v", d, w.maxBackoff)
+		}
+		if b := w.backoff(1); b <= 0 {
+			t.Errorf("退避时间应为正数，实际 %v", b)
+		}
+	}
+	for _, n := range []int{0, -1} {
+		if w := NewWatcher(nil, WithWatchBuffer(n)); w.buffer != 64 {
+			t.Errorf("WithWatchBuffer(%d) 后 buffer = %d", n, w.buffer)
+		}
+	}
+}
+
+func TestWatcherBackoff(t *testing.T) {
+	w := NewWatcher(nil, WithWatchInterval(time.Second), WithWatchMaxBackoff(5*time.Second))
+	for n, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := w.backoff(n); got != want {
+			t.Errorf("backoff(%d) = %v, 期望 %v", n, got, want)
+		}
+	}
+}
+
+func TestWatcherFirstSnapshotAndSinglePoll(t *testing.T) {
+	var mu sync.Mutex
+	torrents := []Torrent{{Hash: "a", ClientAlias: "qb", ClientID: "qb", State: "downloading"}}
+	qb := DownloaderInfo{Status: true}
+	qb.ID = "qb"
+
+	var downloaderCalls, torrentCalls int32
+	firstPolled := make(chan struct{})
+	listTorrents := torrentListRoute(func() []Torrent {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Torrent(nil), torrents...)
+	})
+	c, _ := newTestClient(t, map[string]testRoute{
+		"/api/downloader/list": func(*http.Request) (interface{}, error) {
+			atomic.AddInt32(&downloaderCalls, 1)
+			return []DownloaderInfo{qb}, nil
+		},
+		"/api/torrent/list": func(r *http.Request) (interface{}, error) {
+			n := atomic.AddInt32(&torrentCalls, 1)
+			// 每次轮询只请求一次下载器列表
+			if d := atomic.LoadInt32(&downloaderCalls); d != n {
+				t.Errorf("第 %d 次轮询时已请求 %d 次下载器列表", n, d)
+			}
+			data, err := listTorrents(r)
+			if n == 1 {
+				close(firstPolled)
+			}
+			return data, err
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := NewWatcher(c, WithWatchInterval(20*time.Millisecond)).Watch(ctx)
+
+	// 首次轮询已取得数据后再修改，保证基线快照中种子仍为 downloading
+	select {
+	case <-firstPolled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("首次轮询未完成")
+	}
+	mu.Lock()
+	torrents[0].State = "seeding"
+	mu.Unlock()
+
+	// 首次快照不产生事件，因此收到的第一个事件就是状态变化
+	select {
+	case ev := <-events:
+		if ev.Type != EventTorrentStateChanged || ev.PrevTorrent.State != "downloading" {
+			t.Fatalf("事件 = %v (%v)", ev.Type, ev.Err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("未收到状态变化事件")
+	}
+}